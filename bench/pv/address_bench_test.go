@@ -0,0 +1,292 @@
+package pv
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/btcsuite/btcd/btcec/v2/schnorr"
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/btcsuite/btcd/chaincfg"
+	"github.com/btcsuite/btcd/txscript"
+	"github.com/btcsuite/btcwallet/waddrmgr"
+	"github.com/btcsuite/btcwallet/walletdb"
+)
+
+// addrKind is an address type the address benchmarks can generate.
+type addrKind int
+
+const (
+	addrP2WPKH addrKind = iota
+	addrP2TR
+)
+
+var addrKindNames = [...]string{
+	addrP2WPKH: "P2WPKH",
+	addrP2TR:   "P2TR",
+}
+
+// makeP2WPKHAddress returns the P2WPKH address of key index i.
+func makeP2WPKHAddress(i int) *btcutil.AddressWitnessPubKeyHash {
+	_, pub := scriptKey(i)
+	a, err := btcutil.NewAddressWitnessPubKeyHash(btcutil.Hash160(pub.SerializeCompressed()), &chaincfg.MainNetParams)
+	if err != nil {
+		panic(err)
+	}
+	return a
+}
+
+// makeP2TRAddress returns the key-path-only P2TR address of key index i.
+func makeP2TRAddress(i int) *btcutil.AddressTaproot {
+	_, pub := scriptKey(i)
+	key := txscript.ComputeTaprootKeyNoScript(pub)
+	a, err := btcutil.NewAddressTaproot(schnorr.SerializePubKey(key), &chaincfg.MainNetParams)
+	if err != nil {
+		panic(err)
+	}
+	return a
+}
+
+// makeAddress returns address i of the given kind with its wallet address
+// type.
+func makeAddress(i int, kind addrKind) (btcutil.Address, waddrmgr.AddressType) {
+	if kind == addrP2TR {
+		return makeP2TRAddress(i), waddrmgr.TaprootPubKey
+	}
+	return makeP2WPKHAddress(i), waddrmgr.WitnessPubKey
+}
+
+// fixtureAddress is a waddrmgr.ManagedAddress backed by a plain
+// btcutil.Address. The managers' own implementations need an open address
+// manager, so the benchmarks use this to get the same interface dispatch.
+type fixtureAddress struct {
+	account  uint32
+	addr     btcutil.Address
+	internal bool
+	addrType waddrmgr.AddressType
+}
+
+var _ waddrmgr.ManagedAddress = (*fixtureAddress)(nil)
+
+func (a *fixtureAddress) InternalAccount() uint32          { return a.account }
+func (a *fixtureAddress) Address() btcutil.Address         { return a.addr }
+func (a *fixtureAddress) AddrHash() []byte                 { return a.addr.ScriptAddress() }
+func (a *fixtureAddress) Imported() bool                   { return false }
+func (a *fixtureAddress) Internal() bool                   { return a.internal }
+func (a *fixtureAddress) Compressed() bool                 { return true }
+func (a *fixtureAddress) Used(ns walletdb.ReadBucket) bool { return false }
+func (a *fixtureAddress) AddrType() waddrmgr.AddressType   { return a.addrType }
+
+type addressParams struct {
+	numAddrs int
+	kind     addrKind
+	encode   bool
+}
+
+// addressDims names the address count, address kind and called method
+// dimensions. Kind and method are categorical: their growth index selects a
+// name. Example prefix: "04096-Addrs-P2TR-EncodeAddress".
+func addressDims(addrGrowth growthFunc) []dimension[addressParams] {
+	return []dimension[addressParams]{
+		{label: "Addrs", growth: addrGrowth, set: func(p *addressParams, v int) { p.numAddrs = v }},
+		{
+			label:  "Kind",
+			growth: func(i int) int { return i },
+			set:    func(p *addressParams, v int) { p.kind = addrKind(v) },
+			steps:  len(addrKindNames),
+			format: func(v, _ int) string { return "-" + addrKindNames[v] },
+		},
+		{
+			label:  "Method",
+			growth: func(i int) int { return i },
+			set:    func(p *addressParams, v int) { p.encode = v == 1 },
+			steps:  2,
+			format: func(v, _ int) string {
+				if v == 1 {
+					return "-EncodeAddress"
+				}
+				return "-ScriptAddress"
+			},
+		},
+	}
+}
+
+// readAddress is the per-element work of the address benchmarks: the bech32
+// string when encode is set, the witness program otherwise.
+func readAddress(a btcutil.Address, encode bool) int64 {
+	if encode {
+		return int64(len(a.EncodeAddress()))
+	}
+	s := a.ScriptAddress()
+	return int64(len(s)) + int64(s[0])
+}
+
+// BenchmarkAddress_SliceIterate benchmarks calling address methods through
+// the three ways a wallet stores addresses:
+//   - 0-Concrete: a slice of the concrete btcutil address struct, with
+//     statically dispatched method calls.
+//   - 1-Interface: a []btcutil.Address, boxing a pointer per element.
+//   - 2-Managed: a []waddrmgr.ManagedAddress, a second interface hop in
+//     front of the btcutil.Address.
+func BenchmarkAddress_SliceIterate(b *testing.B) {
+	datasets := generateSweep(sweepConfig[addressParams]{
+		dims:       addressDims(scaleGrowth(8, exponentialGrowth())),
+		iterations: 8,
+	})
+	for _, d := range datasets {
+		n, encode := d.p.numAddrs, d.p.encode
+		ifaces := make([]btcutil.Address, n)
+		managed := make([]waddrmgr.ManagedAddress, n)
+		for i := range ifaces {
+			addr, addrType := makeAddress(i, d.p.kind)
+			ifaces[i] = addr
+			managed[i] = &fixtureAddress{
+				account:  uint32(i % 4),
+				addr:     addr,
+				internal: i%2 == 1,
+				addrType: addrType,
+			}
+		}
+		prefix := d.name()
+		switch d.p.kind {
+		case addrP2WPKH:
+			concrete := make([]btcutil.AddressWitnessPubKeyHash, n)
+			for i := range concrete {
+				concrete[i] = *makeP2WPKHAddress(i)
+			}
+			b.Run(prefix+"/0-Concrete", func(b *testing.B) {
+				b.ReportAllocs()
+				var acc int64
+				for b.Loop() {
+					for i := range concrete {
+						a := &concrete[i]
+						if encode {
+							acc += int64(len(a.EncodeAddress()))
+							continue
+						}
+						s := a.ScriptAddress()
+						acc += int64(len(s)) + int64(s[0])
+					}
+				}
+				sinkI64 = acc
+			})
+		case addrP2TR:
+			concrete := make([]btcutil.AddressTaproot, n)
+			for i := range concrete {
+				concrete[i] = *makeP2TRAddress(i)
+			}
+			b.Run(prefix+"/0-Concrete", func(b *testing.B) {
+				b.ReportAllocs()
+				var acc int64
+				for b.Loop() {
+					for i := range concrete {
+						a := &concrete[i]
+						if encode {
+							acc += int64(len(a.EncodeAddress()))
+							continue
+						}
+						s := a.ScriptAddress()
+						acc += int64(len(s)) + int64(s[0])
+					}
+				}
+				sinkI64 = acc
+			})
+		}
+		b.Run(prefix+"/1-Interface", func(b *testing.B) {
+			b.ReportAllocs()
+			var acc int64
+			for b.Loop() {
+				for _, a := range ifaces {
+					acc += readAddress(a, encode)
+				}
+			}
+			sinkI64 = acc
+		})
+		b.Run(prefix+"/2-Managed", func(b *testing.B) {
+			b.ReportAllocs()
+			var acc int64
+			for b.Loop() {
+				for _, m := range managed {
+					acc += readAddress(m.Address(), encode)
+					acc += int64(m.AddrType()) + int64(m.InternalAccount())
+					if m.Internal() {
+						acc++
+					}
+				}
+			}
+			sinkI64 = acc
+		})
+	}
+}
+
+// readUtxoAddress is readUtxo plus the address, read through the interface.
+func readUtxoAddress(u *Utxo) int64 {
+	return readUtxo(u) + readAddress(u.Address, false) + int64(u.AddressType)
+}
+
+// sumUtxoAddressValues is readUtxoAddress over a []Utxo, reading the fields
+// of val directly as sumUtxoValues does.
+func sumUtxoAddressValues(vals []Utxo) int64 {
+	var acc int64
+	for _, val := range vals {
+		acc += int64(val.Amount) + int64(len(val.PkScript)) + int64(val.Confirmations)
+		acc += readAddress(val.Address, false) + int64(val.AddressType)
+	}
+	return acc
+}
+
+// sumUtxoAddressPointers is readUtxoAddress over a []*Utxo.
+func sumUtxoAddressPointers(ptrs []*Utxo) int64 {
+	var acc int64
+	for _, ptr := range ptrs {
+		acc += readUtxoAddress(ptr)
+	}
+	return acc
+}
+
+// utxoAddressSuite builds Utxos whose Address field holds real P2WPKH and
+// P2TR addresses, alternating, instead of nil. Addresses and the pkScript are
+// made once per dataset; the builders only store them. Iteration calls
+// ScriptAddress through the interface on every element.
+var utxoAddressSuite = pvSuite[utxoParams, Utxo]{
+	builders: func(p utxoParams) (func() []Utxo, func() []*Utxo) {
+		pkScript := makePkScript(p.scriptSize)
+		addrs := make([]btcutil.Address, p.numUtxos)
+		types := make([]waddrmgr.AddressType, p.numUtxos)
+		for i := range addrs {
+			addrs[i], types[i] = makeAddress(i, addrKind(i%2))
+		}
+		values := func() []Utxo {
+			s := buildUtxoValues(p.numUtxos, pkScript)
+			for i := range s {
+				s[i].Address, s[i].AddressType = addrs[i], types[i]
+			}
+			return s
+		}
+		pointers := func() []*Utxo {
+			s := buildUtxoPointers(p.numUtxos, pkScript)
+			for i, u := range s {
+				u.Address, u.AddressType = addrs[i], types[i]
+			}
+			return s
+		}
+		return values, pointers
+	},
+	read:        readUtxoAddress,
+	sumValues:   sumUtxoAddressValues,
+	sumPointers: sumUtxoAddressPointers,
+	prefix: func(d dataset[utxoParams]) string {
+		return fmt.Sprintf("%s-Addrs", d.name())
+	},
+}
+
+// BenchmarkUtxo_AddressSliceBuild benchmarks building slices of Utxo values
+// vs pointers that carry real addresses.
+func BenchmarkUtxo_AddressSliceBuild(b *testing.B) {
+	utxoAddressSuite.sliceBuild(b, utxoDatasets())
+}
+
+// BenchmarkUtxo_AddressSliceIterate benchmarks iterating over slices of Utxo
+// values vs pointers while dispatching through each element's Address.
+func BenchmarkUtxo_AddressSliceIterate(b *testing.B) {
+	utxoAddressSuite.sliceIterate(b, utxoDatasets())
+}
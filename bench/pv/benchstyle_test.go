@@ -1,6 +1,10 @@
 package pv
 
-import "fmt"
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
 
 // sink variables prevent the compiler from optimizing away benchmarked work.
 // Shared across all benchmarks in this package.
@@ -35,202 +39,169 @@ func scaleGrowth(base int, g growthFunc) growthFunc {
 	return func(i int) int { return base * g(i) }
 }
 
-type utxoDataset struct {
-	numUtxos   int
-	scriptSize int
-	maxUtxos   int
-	maxScript  int
+// dimension is one named axis of a dataset. growth yields its value at each
+// iteration and set stores that value into the dataset parameters P.
+type dimension[P any] struct {
+	label  string
+	growth growthFunc
+	set    func(p *P, v int)
+
+	// steps is the number of values the dimension takes in a sweep. Zero
+	// means the sweep's iterations.
+	steps int
+
+	// format renders the value as a b.Run prefix segment, including its
+	// leading separator. Nil means "-<value>-<label>" with the value
+	// zero-padded to the widest value the dimension reaches.
+	format func(v, width int) string
+}
+
+func (dim dimension[P]) segment(v, width int) string {
+	if dim.format != nil {
+		return dim.format(v, width)
+	}
+	return fmt.Sprintf("-%0*d-%s", width, v, dim.label)
+}
+
+// dataset is a single point across the dimensions of a benchConfig or
+// sweepConfig. labels and segments hold one entry per dimension.
+type dataset[P any] struct {
+	p        P
+	labels   []string
+	segments []string
 }
 
 // name returns a prefix used for b.Run to group runs in tools like vizb/benchstat.
 // Example: "04096-Utxos-0034-Script".
-func (d utxoDataset) name() string {
-	uDigits := len(fmt.Sprintf("%d", d.maxUtxos))
-	sDigits := len(fmt.Sprintf("%d", d.maxScript))
-	return fmt.Sprintf("%0*d-Utxos-%0*d-Script", uDigits, d.numUtxos, sDigits, d.scriptSize)
+func (d dataset[P]) name() string {
+	return strings.TrimPrefix(strings.Join(d.segments, ""), "-")
+}
+
+// groupBy splits the name around the dimension with the given label. x is
+// that dimension's segment and series is every other dimension.
+// Example: groupBy("Utxos") on "04096-Utxos-0034-Script" returns
+// "04096-Utxos", "0034-Script".
+func (d dataset[P]) groupBy(label string) (x, series string) {
+	var sb strings.Builder
+	for k, seg := range d.segments {
+		if d.labels[k] == label {
+			x = strings.TrimPrefix(seg, "-")
+			continue
+		}
+		sb.WriteString(seg)
+	}
+	return x, strings.TrimPrefix(sb.String(), "-")
 }
 
-type utxoBenchConfig struct {
-	utxoGrowth   growthFunc
-	scriptGrowth growthFunc
-	iterations   int
+type benchConfig[P any] struct {
+	dims       []dimension[P]
+	iterations int
 }
 
-func generateUtxoDatasets(c utxoBenchConfig) []utxoDataset {
+// generateDatasets advances every dimension together on the same iteration
+// index and returns one dataset per iteration.
+func generateDatasets[P any](c benchConfig[P]) []dataset[P] {
 	if c.iterations < 1 {
 		c.iterations = 1
 	}
-	maxU := c.utxoGrowth(c.iterations - 1)
-	maxS := c.scriptGrowth(c.iterations - 1)
-	out := make([]utxoDataset, 0, c.iterations)
+	widths := make([]int, len(c.dims))
+	for k, dim := range c.dims {
+		widths[k] = digits(dim.growth(c.iterations - 1))
+	}
+	out := make([]dataset[P], 0, c.iterations)
+	idx := make([]int, len(c.dims))
 	for i := 0; i < c.iterations; i++ {
-		out = append(out, utxoDataset{
-			numUtxos:   c.utxoGrowth(i),
-			scriptSize: c.scriptGrowth(i),
-			maxUtxos:   maxU,
-			maxScript:  maxS,
-		})
+		for k := range idx {
+			idx[k] = i
+		}
+		out = append(out, newDataset(c.dims, idx, widths))
 	}
 	return out
 }
 
-type outpointDataset struct {
-	numOutPoints int
-	maxOutPoints int
+// newDataset resolves every dimension at its own iteration index.
+func newDataset[P any](dims []dimension[P], idx, widths []int) dataset[P] {
+	d := dataset[P]{
+		labels:   make([]string, len(dims)),
+		segments: make([]string, len(dims)),
+	}
+	for k, dim := range dims {
+		v := dim.growth(idx[k])
+		dim.set(&d.p, v)
+		d.labels[k] = dim.label
+		d.segments[k] = dim.segment(v, widths[k])
+	}
+	return d
 }
 
-// name returns a prefix used for b.Run to group runs for OutPoint.
-// Example: "04096-OutPoints".
-func (d outpointDataset) name() string {
-	uDigits := len(fmt.Sprintf("%d", d.maxOutPoints))
-	return fmt.Sprintf("%0*d-OutPoints", uDigits, d.numOutPoints)
+// singleDataset returns the dataset at iteration 0, for benchmarks that run
+// at one fixed size.
+func singleDataset[P any](dims []dimension[P]) dataset[P] {
+	return generateDatasets(benchConfig[P]{dims: dims, iterations: 1})[0]
 }
 
-type outpointBenchConfig struct {
-	outpointGrowth growthFunc
-	iterations     int
+// sweepConfig describes a grid of datasets: every combination of dimension
+// values rather than the lock-step progression of generateDatasets.
+type sweepConfig[P any] struct {
+	dims       []dimension[P]
+	iterations int
+
+	// keep selects a subset of the grid. Nil keeps every combination.
+	keep func(p P) bool
 }
 
-func generateOutPointDatasets(c outpointBenchConfig) []outpointDataset {
+// generateSweep returns the cartesian product of the dimensions, with the
+// first dimension varying slowest.
+func generateSweep[P any](c sweepConfig[P]) []dataset[P] {
 	if c.iterations < 1 {
 		c.iterations = 1
 	}
-	maxU := c.outpointGrowth(c.iterations - 1)
-	out := make([]outpointDataset, 0, c.iterations)
-	for i := 0; i < c.iterations; i++ {
-		out = append(out, outpointDataset{
-			numOutPoints: c.outpointGrowth(i),
-			maxOutPoints: maxU,
-		})
+	steps := make([]int, len(c.dims))
+	widths := make([]int, len(c.dims))
+	for k, dim := range c.dims {
+		steps[k] = dim.steps
+		if steps[k] < 1 {
+			steps[k] = c.iterations
+		}
+		widths[k] = digits(dim.growth(steps[k] - 1))
+	}
+	var out []dataset[P]
+	idx := make([]int, len(c.dims))
+	for {
+		d := newDataset(c.dims, idx, widths)
+		if c.keep == nil || c.keep(d.p) {
+			out = append(out, d)
+		}
+		k := len(idx) - 1
+		for ; k >= 0; k-- {
+			idx[k]++
+			if idx[k] < steps[k] {
+				break
+			}
+			idx[k] = 0
+		}
+		if k < 0 {
+			return out
+		}
 	}
-	return out
 }
 
-// ---- Additional dataset helpers for TxIn, TxOut, and MsgTx ----
-
-type txinDataset struct {
-    numTxIns   int
-    scriptSize int
-    maxTxIns   int
-    maxScript  int
-}
-
-// name returns a prefix for TxIn dataset. Example: "04096-TxIns-0034-Sig".
-func (d txinDataset) name() string {
-    uDigits := len(fmt.Sprintf("%d", d.maxTxIns))
-    sDigits := len(fmt.Sprintf("%d", d.maxScript))
-    return fmt.Sprintf("%0*d-TxIns-%0*d-Sig", uDigits, d.numTxIns, sDigits, d.scriptSize)
-}
-
-type txinBenchConfig struct {
-    txinGrowth   growthFunc
-    scriptGrowth growthFunc
-    iterations   int
-}
-
-func generateTxInDatasets(c txinBenchConfig) []txinDataset {
-    if c.iterations < 1 {
-        c.iterations = 1
-    }
-    maxU := c.txinGrowth(c.iterations - 1)
-    maxS := c.scriptGrowth(c.iterations - 1)
-    out := make([]txinDataset, 0, c.iterations)
-    for i := 0; i < c.iterations; i++ {
-        out = append(out, txinDataset{
-            numTxIns:   c.txinGrowth(i),
-            scriptSize: c.scriptGrowth(i),
-            maxTxIns:   maxU,
-            maxScript:  maxS,
-        })
-    }
-    return out
-}
-
-type txoutDataset struct {
-    numTxOuts  int
-    scriptSize int
-    maxTxOuts  int
-    maxScript  int
-}
-
-// name returns a prefix for TxOut dataset. Example: "04096-TxOuts-0034-Script".
-func (d txoutDataset) name() string {
-    uDigits := len(fmt.Sprintf("%d", d.maxTxOuts))
-    sDigits := len(fmt.Sprintf("%d", d.maxScript))
-    return fmt.Sprintf("%0*d-TxOuts-%0*d-Script", uDigits, d.numTxOuts, sDigits, d.scriptSize)
-}
-
-type txoutBenchConfig struct {
-    txoutGrowth  growthFunc
-    scriptGrowth growthFunc
-    iterations   int
-}
-
-func generateTxOutDatasets(c txoutBenchConfig) []txoutDataset {
-    if c.iterations < 1 {
-        c.iterations = 1
-    }
-    maxU := c.txoutGrowth(c.iterations - 1)
-    maxS := c.scriptGrowth(c.iterations - 1)
-    out := make([]txoutDataset, 0, c.iterations)
-    for i := 0; i < c.iterations; i++ {
-        out = append(out, txoutDataset{
-            numTxOuts:  c.txoutGrowth(i),
-            scriptSize: c.scriptGrowth(i),
-            maxTxOuts:  maxU,
-            maxScript:  maxS,
-        })
-    }
-    return out
-}
-
-type msgtxDataset struct {
-    numTxs     int
-    scriptSize int
-    nInputs    int
-    nOutputs   int
-    maxTxs     int
-    maxScript  int
-}
-
-// name returns a prefix for MsgTx dataset.
-// Example: "04096-Txs-0034-Script-2x2" where 2x2 are in/out counts.
-func (d msgtxDataset) name() string {
-    uDigits := len(fmt.Sprintf("%d", d.maxTxs))
-    sDigits := len(fmt.Sprintf("%d", d.maxScript))
-    return fmt.Sprintf("%0*d-Txs-%0*d-Script-%dx%d", uDigits, d.numTxs, sDigits, d.scriptSize, d.nInputs, d.nOutputs)
-}
-
-type msgtxBenchConfig struct {
-    txGrowth     growthFunc
-    scriptGrowth growthFunc
-    iterations   int
-    nInputs      int
-    nOutputs     int
-}
-
-func generateMsgTxDatasets(c msgtxBenchConfig) []msgtxDataset {
-    if c.iterations < 1 {
-        c.iterations = 1
-    }
-    if c.nInputs <= 0 {
-        c.nInputs = 2
-    }
-    if c.nOutputs <= 0 {
-        c.nOutputs = 2
-    }
-    maxU := c.txGrowth(c.iterations - 1)
-    maxS := c.scriptGrowth(c.iterations - 1)
-    out := make([]msgtxDataset, 0, c.iterations)
-    for i := 0; i < c.iterations; i++ {
-        out = append(out, msgtxDataset{
-            numTxs:     c.txGrowth(i),
-            scriptSize: c.scriptGrowth(i),
-            nInputs:    c.nInputs,
-            nOutputs:   c.nOutputs,
-            maxTxs:     maxU,
-            maxScript:  maxS,
-        })
-    }
-    return out
+// runSweep passes every dataset to fn once, with the prefix "<x>/<series>"
+// from groupBy(axis), so the axis is the workload and each fixed value of the
+// other dimensions draws its own curve.
+// Example: "04096-Utxos/0034-Script".
+func runSweep[P any](b *testing.B, datasets []dataset[P], axis string, fn func(b *testing.B, d dataset[P], prefix string)) {
+	for _, d := range datasets {
+		x, series := d.groupBy(axis)
+		fn(b, d, x+"/"+series)
+	}
 }
+
+// digits returns the number of decimal digits in v.
+func digits(v int) int {
+	return len(fmt.Sprintf("%d", v))
+}
+
+// parallelProcs are the GOMAXPROCS values swept by the parallel reader
+// benchmarks.
+var parallelProcs = []int{1, 2, 4, 8}
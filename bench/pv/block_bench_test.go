@@ -0,0 +1,399 @@
+package pv
+
+import (
+	"bytes"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcd/wire"
+)
+
+// blockTxShape is an input/output count and its share of block transactions.
+type blockTxShape struct {
+	nIn, nOut int
+	weight    int
+}
+
+// blockTxShapes approximate the mix of a recent mainnet block: mostly
+// payments with change, some sweeps and multi-input spends, and a tail of
+// consolidations and batched payouts that dominate the input and output
+// totals.
+var blockTxShapes = []blockTxShape{
+	{nIn: 1, nOut: 2, weight: 45},
+	{nIn: 2, nOut: 2, weight: 20},
+	{nIn: 1, nOut: 1, weight: 15},
+	{nIn: 3, nOut: 2, weight: 8},
+	{nIn: 2, nOut: 1, weight: 2},
+	{nIn: 8, nOut: 1, weight: 5},
+	{nIn: 1, nOut: 20, weight: 5},
+}
+
+// pickBlockTxShape returns the shape of transaction i.
+func pickBlockTxShape(i int) blockTxShape {
+	total := 0
+	for _, s := range blockTxShapes {
+		total += s.weight
+	}
+	r := scatter(i, total)
+	for _, s := range blockTxShapes {
+		if r < s.weight {
+			return s
+		}
+		r -= s.weight
+	}
+	return blockTxShapes[len(blockTxShapes)-1]
+}
+
+// blockScripts is a pool of output scripts in the wallet-like mix, generated
+// on first use. Block outputs copy from it.
+var blockScripts = sync.OnceValue(func() [][]byte {
+	return scriptMixes[len(scriptMixes)-1].scripts(256)
+})
+
+// makeCoinbaseTx returns the coinbase of the block at height: a height-push
+// signature script, the witness reserved value and a reward output plus the
+// witness commitment.
+func makeCoinbaseTx(height int32) *wire.MsgTx {
+	sigScript := make([]byte, 40)
+	sigScript[0], sigScript[1], sigScript[2], sigScript[3] = 3, byte(height), byte(height>>8), byte(height>>16)
+	return &wire.MsgTx{
+		Version: 2,
+		TxIn: []*wire.TxIn{{
+			PreviousOutPoint: wire.OutPoint{Index: wire.MaxPrevOutIndex},
+			SignatureScript:  sigScript,
+			Witness:          wire.TxWitness{make([]byte, 32)},
+			Sequence:         wire.MaxTxInSequenceNum,
+		}},
+		TxOut: []*wire.TxOut{
+			{Value: 312500000, PkScript: bytes.Clone(blockScripts()[0])},
+			{Value: 0, PkScript: make([]byte, 38)},
+		},
+	}
+}
+
+// makeBlockTx returns the non-coinbase transaction i of a block, with its
+// shape from blockTxShapes. Inputs are native segwit spends, one in five
+// taproot; outputs copy scripts from blockScripts.
+func makeBlockTx(i int) *wire.MsgTx {
+	shape := pickBlockTxShape(i)
+	scripts := blockScripts()
+	tx := &wire.MsgTx{
+		Version:  2,
+		TxIn:     make([]*wire.TxIn, shape.nIn),
+		TxOut:    make([]*wire.TxOut, shape.nOut),
+		LockTime: 0,
+	}
+	for j := range tx.TxIn {
+		witness := witnessP2WPKH
+		if (i+j)%5 == 0 {
+			witness = witnessP2TRKeyPath
+		}
+		tx.TxIn[j] = &wire.TxIn{
+			PreviousOutPoint: wire.OutPoint{
+				Hash:  chainhash.Hash{byte(i), byte(i >> 8), byte(j)},
+				Index: uint32(j),
+			},
+			SignatureScript: []byte{},
+			Witness:         makeWitness(witness),
+			Sequence:        wire.MaxTxInSequenceNum - 2,
+		}
+	}
+	for k := range tx.TxOut {
+		tx.TxOut[k] = &wire.TxOut{
+			Value:    int64(10000 + i*7 + k),
+			PkScript: bytes.Clone(scripts[(i+k)%len(scripts)]),
+		}
+	}
+	return tx
+}
+
+// makeBlock builds a block of nTxs transactions, the first a coinbase, the
+// way wire decodes one: Transactions is a []*wire.MsgTx.
+func makeBlock(nTxs int) *wire.MsgBlock {
+	const height = 850000
+	blk := &wire.MsgBlock{
+		Header:       makeBlockHeader(height, chainhash.Hash{1}),
+		Transactions: make([]*wire.MsgTx, nTxs),
+	}
+	blk.Transactions[0] = makeCoinbaseTx(height)
+	for i := 1; i < nTxs; i++ {
+		blk.Transactions[i] = makeBlockTx(i)
+	}
+	return blk
+}
+
+// copyBlockTxs returns a []wire.MsgTx copy of blk's transactions. The copy is
+// shallow: inputs, outputs and scripts are shared with blk.
+func copyBlockTxs(blk *wire.MsgBlock) []wire.MsgTx {
+	txs := make([]wire.MsgTx, len(blk.Transactions))
+	for i, tx := range blk.Transactions {
+		txs[i] = *tx
+	}
+	return txs
+}
+
+// makeBlockHeader returns the header at height, linked to prev.
+func makeBlockHeader(height int32, prev chainhash.Hash) wire.BlockHeader {
+	return wire.BlockHeader{
+		Version:    0x20000000,
+		PrevBlock:  prev,
+		MerkleRoot: chainhash.Hash{byte(height), byte(height >> 8), byte(height >> 16)},
+		Timestamp:  wtxmgrEpoch.Add(time.Duration(height) * 10 * time.Minute),
+		Bits:       0x17034219,
+		Nonce:      uint32(height) * 2654435761,
+	}
+}
+
+// makeHeaderChain returns n headers, each linked to the hash of the one
+// before it.
+func makeHeaderChain(n int) []wire.BlockHeader {
+	headers := make([]wire.BlockHeader, n)
+	var prev chainhash.Hash
+	for i := range headers {
+		headers[i] = makeBlockHeader(int32(i), prev)
+		prev = headers[i].BlockHash()
+	}
+	return headers
+}
+
+type blockParams struct {
+	numTxs int
+}
+
+// blockTxCounts are the block sizes the block benchmarks step through, from
+// an empty block to a full one.
+var blockTxCounts = []int{1, 16, 128, 512, 1024, 2048, 4000}
+
+// blockDims names the transactions-per-block dimension.
+// Example prefix: "4000-BlockTxs".
+func blockDims(txGrowth growthFunc) []dimension[blockParams] {
+	return []dimension[blockParams]{
+		{label: "BlockTxs", growth: txGrowth, set: func(p *blockParams, v int) { p.numTxs = v }},
+	}
+}
+
+// blockDatasets steps through blockTxCounts.
+func blockDatasets() []dataset[blockParams] {
+	return generateDatasets(benchConfig[blockParams]{
+		dims:       blockDims(func(i int) int { return blockTxCounts[i] }),
+		iterations: len(blockTxCounts),
+	})
+}
+
+// blockSuite compares a []wire.MsgTx copy of a block's transactions, as
+// "0-Values", against the upstream []*wire.MsgTx, as "1-Pointers". read and
+// the sums are set per benchmark.
+func blockSuite(read func(tx *wire.MsgTx) int64, sumValues func(vals []wire.MsgTx) int64,
+	sumPointers func(ptrs []*wire.MsgTx) int64) pvSuite[blockParams, wire.MsgTx] {
+
+	return pvSuite[blockParams, wire.MsgTx]{
+		builders: func(p blockParams) (func() []wire.MsgTx, func() []*wire.MsgTx) {
+			return func() []wire.MsgTx { return copyBlockTxs(makeBlock(p.numTxs)) },
+				func() []*wire.MsgTx { return makeBlock(p.numTxs).Transactions }
+		},
+		read:        read,
+		sumValues:   sumValues,
+		sumPointers: sumPointers,
+	}
+}
+
+// readBlockTx sums the output values, input indexes and witness sizes of tx.
+func readBlockTx(tx *wire.MsgTx) int64 {
+	var acc int64
+	for _, ti := range tx.TxIn {
+		acc += int64(ti.PreviousOutPoint.Index) + readWitness(ti.Witness)
+	}
+	for _, to := range tx.TxOut {
+		acc += to.Value
+	}
+	return acc
+}
+
+// sumBlockTxValues is readBlockTx over a []wire.MsgTx.
+func sumBlockTxValues(vals []wire.MsgTx) int64 {
+	var acc int64
+	for _, val := range vals {
+		acc += readBlockTx(&val)
+	}
+	return acc
+}
+
+// sumBlockTxPointers is readBlockTx over a []*wire.MsgTx.
+func sumBlockTxPointers(ptrs []*wire.MsgTx) int64 {
+	var acc int64
+	for _, ptr := range ptrs {
+		acc += readBlockTx(ptr)
+	}
+	return acc
+}
+
+// BenchmarkMsgBlock_Sum benchmarks a block-wide pass summing output values,
+// input counts and witness sizes, as a fee or size estimator does.
+func BenchmarkMsgBlock_Sum(b *testing.B) {
+	blockSuite(readBlockTx, sumBlockTxValues, sumBlockTxPointers).sliceIterate(b, blockDatasets())
+}
+
+// BenchmarkMsgBlock_TxHash benchmarks computing the txid of every
+// transaction in a block.
+func BenchmarkMsgBlock_TxHash(b *testing.B) {
+	blockSuite(hashMsgTx, sumMsgTxHashValues, sumMsgTxHashPointers).sliceIterate(b, blockDatasets())
+}
+
+// BenchmarkMsgBlock_Serialize benchmarks encoding a whole block into a
+// reused buffer. "1-Pointers" is the upstream MsgBlock.Serialize;
+// "0-Values" writes the same bytes from a []wire.MsgTx copy.
+func BenchmarkMsgBlock_Serialize(b *testing.B) {
+	for _, d := range blockDatasets() {
+		blk := makeBlock(d.p.numTxs)
+		txs := copyBlockTxs(blk)
+		var buf bytes.Buffer
+		buf.Grow(blk.SerializeSize())
+		prefix := d.name()
+		b.Run(prefix+"/0-Values", func(b *testing.B) {
+			b.ReportAllocs()
+			m := startGCMeter()
+			for b.Loop() {
+				buf.Reset()
+				if err := blk.Header.Serialize(&buf); err != nil {
+					b.Fatal(err)
+				}
+				if err := wire.WriteVarInt(&buf, 0, uint64(len(txs))); err != nil {
+					b.Fatal(err)
+				}
+				for i := range txs {
+					if err := txs[i].Serialize(&buf); err != nil {
+						b.Fatal(err)
+					}
+				}
+			}
+			m.report(b)
+			sinkInt = buf.Len()
+		})
+		b.Run(prefix+"/1-Pointers", func(b *testing.B) {
+			b.ReportAllocs()
+			m := startGCMeter()
+			for b.Loop() {
+				buf.Reset()
+				if err := blk.Serialize(&buf); err != nil {
+					b.Fatal(err)
+				}
+			}
+			m.report(b)
+			sinkInt = buf.Len()
+		})
+	}
+}
+
+type headerParams struct {
+	numHeaders int
+}
+
+// headerDims names the header chain length dimension.
+// Example prefix: "131072-Headers".
+func headerDims(headerGrowth growthFunc) []dimension[headerParams] {
+	return []dimension[headerParams]{
+		{label: "Headers", growth: headerGrowth, set: func(p *headerParams, v int) { p.numHeaders = v }},
+	}
+}
+
+// headerDatasets is the header chain progression, 1024 headers (about a
+// week) to 131072.
+func headerDatasets() []dataset[headerParams] {
+	return generateDatasets(benchConfig[headerParams]{
+		dims:       headerDims(scaleGrowth(1024, exponentialGrowth())),
+		iterations: 8,
+	})
+}
+
+// headerSuite compares []wire.BlockHeader with []*wire.BlockHeader over one
+// linked chain, made once per dataset and copied by the builders.
+// read and the sums are set per benchmark.
+func headerSuite(read func(h *wire.BlockHeader) int64, sumValues func(vals []wire.BlockHeader) int64,
+	sumPointers func(ptrs []*wire.BlockHeader) int64) pvSuite[headerParams, wire.BlockHeader] {
+
+	return pvSuite[headerParams, wire.BlockHeader]{
+		builders: func(p headerParams) (func() []wire.BlockHeader, func() []*wire.BlockHeader) {
+			chain := makeHeaderChain(p.numHeaders)
+			return func() []wire.BlockHeader {
+					return append([]wire.BlockHeader(nil), chain...)
+				},
+				func() []*wire.BlockHeader {
+					s := make([]*wire.BlockHeader, len(chain))
+					for i := range chain {
+						h := chain[i]
+						s[i] = &h
+					}
+					return s
+				}
+		},
+		read:        read,
+		sumValues:   sumValues,
+		sumPointers: sumPointers,
+	}
+}
+
+// scanHeader reads the difficulty, timestamp and parent link of h.
+func scanHeader(h *wire.BlockHeader) int64 {
+	return int64(h.Bits) + h.Timestamp.Unix() + int64(h.PrevBlock[0])
+}
+
+// sumHeaderScanValues is scanHeader over a []wire.BlockHeader.
+func sumHeaderScanValues(vals []wire.BlockHeader) int64 {
+	var acc int64
+	for _, val := range vals {
+		acc += scanHeader(&val)
+	}
+	return acc
+}
+
+// sumHeaderScanPointers is scanHeader over a []*wire.BlockHeader.
+func sumHeaderScanPointers(ptrs []*wire.BlockHeader) int64 {
+	var acc int64
+	for _, ptr := range ptrs {
+		acc += scanHeader(ptr)
+	}
+	return acc
+}
+
+// hashHeader computes the block hash of h.
+func hashHeader(h *wire.BlockHeader) int64 {
+	hash := h.BlockHash()
+	return int64(hash[0])
+}
+
+// sumHeaderHashValues is hashHeader over a []wire.BlockHeader. BlockHash
+// leaks its receiver, so every header is copied into one variable rather
+// than escaping each copy.
+func sumHeaderHashValues(vals []wire.BlockHeader) int64 {
+	var cur wire.BlockHeader
+	var acc int64
+	for _, val := range vals {
+		cur = val
+		acc += hashHeader(&cur)
+	}
+	return acc
+}
+
+// sumHeaderHashPointers is hashHeader over a []*wire.BlockHeader.
+func sumHeaderHashPointers(ptrs []*wire.BlockHeader) int64 {
+	var acc int64
+	for _, ptr := range ptrs {
+		acc += hashHeader(ptr)
+	}
+	return acc
+}
+
+// BenchmarkBlockHeader_ChainScan benchmarks a header-chain scan that reads
+// the difficulty, timestamp and parent link of every header, as a chain
+// work or median-time computation does.
+func BenchmarkBlockHeader_ChainScan(b *testing.B) {
+	headerSuite(scanHeader, sumHeaderScanValues, sumHeaderScanPointers).sliceIterate(b, headerDatasets())
+}
+
+// BenchmarkBlockHeader_HashScan benchmarks hashing every header of the chain,
+// the per-header cost of verifying the links.
+func BenchmarkBlockHeader_HashScan(b *testing.B) {
+	headerSuite(hashHeader, sumHeaderHashValues, sumHeaderHashPointers).sliceIterate(b, headerDatasets())
+}
@@ -0,0 +1,180 @@
+package pv
+
+import (
+	"fmt"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"testing"
+)
+
+const (
+	// chanItems is how many elements every channel benchmark op streams.
+	chanItems = 1024
+
+	// chanBatch is the number of elements per send of the batched subjects.
+	chanBatch = 64
+)
+
+// stream sends every element of items, one per send, through a channel with
+// the given buffer to consumers goroutines, which read every element they
+// receive. It returns the sum of the reads once the channel is drained.
+func stream[E any](items []E, buffer, consumers int, read func(e *E) int64) int64 {
+	ch := make(chan E, buffer)
+	var wg sync.WaitGroup
+	var total atomic.Int64
+	wg.Add(consumers)
+	for range consumers {
+		go func() {
+			defer wg.Done()
+			var acc int64
+			// Receiving into one variable keeps the read's pointer from
+			// escaping a fresh copy on every element.
+			var cur E
+			for cur = range ch {
+				acc += read(&cur)
+			}
+			total.Add(acc)
+		}()
+	}
+	for i := range items {
+		ch <- items[i]
+	}
+	close(ch)
+	wg.Wait()
+	return total.Load()
+}
+
+// streamBatches is stream with up to chanBatch elements per send. Each batch
+// is a new slice copied from items, as a producer assembling batches makes.
+func streamBatches[E any](items []E, buffer, consumers int, read func(e *E) int64) int64 {
+	ch := make(chan []E, buffer)
+	var wg sync.WaitGroup
+	var total atomic.Int64
+	wg.Add(consumers)
+	for range consumers {
+		go func() {
+			defer wg.Done()
+			var acc int64
+			for batch := range ch {
+				for i := range batch {
+					acc += read(&batch[i])
+				}
+			}
+			total.Add(acc)
+		}()
+	}
+	for i := 0; i < len(items); i += chanBatch {
+		ch <- slices.Clone(items[i:min(i+chanBatch, len(items))])
+	}
+	close(ch)
+	wg.Wait()
+	return total.Load()
+}
+
+// runStream registers one subject of a channel benchmark. pass streams
+// items elements once; every op starts the channel and consumers afresh.
+func runStream(b *testing.B, name string, items int, pass func() int64) {
+	b.Run(name, func(b *testing.B) {
+		b.ReportAllocs()
+		var acc int64
+		m := startGCMeter()
+		for b.Loop() {
+			acc += pass()
+		}
+		m.report(b)
+		b.ReportMetric(float64(items*b.N)/b.Elapsed().Seconds(), "items/s")
+		sinkI64 = acc
+	})
+}
+
+type chanParams struct {
+	consumers int
+
+	// buffer is the channel capacity, in elements or, for batched
+	// subjects, in batches.
+	buffer int
+}
+
+// chanBuffers are the channel capacities the channel benchmarks sweep.
+var chanBuffers = []int{0, 64}
+
+// chanDims names the consumer count and channel buffer dimensions.
+// Example prefix: "4-Consumers-64-Buffer" or "1-Consumers-Unbuffered".
+func chanDims() []dimension[chanParams] {
+	return []dimension[chanParams]{
+		{
+			label:  "Consumers",
+			growth: func(i int) int { return parallelProcs[i] },
+			set:    func(p *chanParams, v int) { p.consumers = v },
+			steps:  len(parallelProcs),
+		},
+		{
+			label:  "Buffer",
+			growth: func(i int) int { return chanBuffers[i] },
+			set:    func(p *chanParams, v int) { p.buffer = v },
+			steps:  len(chanBuffers),
+			format: func(v, _ int) string {
+				if v == 0 {
+					return "-Unbuffered"
+				}
+				return fmt.Sprintf("-%d-Buffer", v)
+			},
+		},
+	}
+}
+
+// chanSweep is every consumer count with every buffer.
+func chanSweep() []dataset[chanParams] {
+	return generateSweep(sweepConfig[chanParams]{dims: chanDims()})
+}
+
+// streamSuite registers "0-Values", a chan T, and "1-Pointers", a chan *T,
+// for every chanSweep dataset, streaming one build of the suite's dataset p.
+// extra, if set, registers further subjects under the same prefix.
+func streamSuite[P, T any](b *testing.B, s pvSuite[P, T], p P,
+	extra func(prefix string, c chanParams, vals []T, ptrs []*T)) {
+
+	values, pointers := s.builders(p)
+	vals, ptrs := values(), pointers()
+	readPtr := func(e **T) int64 { return s.read(*e) }
+	for _, d := range chanSweep() {
+		prefix := d.name()
+		runStream(b, prefix+"/0-Values", len(vals), func() int64 {
+			return stream(vals, d.p.buffer, d.p.consumers, s.read)
+		})
+		runStream(b, prefix+"/1-Pointers", len(ptrs), func() int64 {
+			return stream(ptrs, d.p.buffer, d.p.consumers, readPtr)
+		})
+		if extra != nil {
+			extra(prefix, d.p, vals, ptrs)
+		}
+	}
+}
+
+// BenchmarkUtxo_Channel benchmarks streaming chanItems Utxos from one
+// producer to several consumers by value and by pointer, one per send, and
+// in batches as a chan []Utxo vs a chan []*Utxo.
+func BenchmarkUtxo_Channel(b *testing.B) {
+	p := utxoParams{numUtxos: chanItems, scriptSize: 34}
+	streamSuite(b, utxoSuite, p, func(prefix string, c chanParams, vals []Utxo, ptrs []*Utxo) {
+		runStream(b, prefix+"/2-BatchValues", len(vals), func() int64 {
+			return streamBatches(vals, c.buffer, c.consumers, readUtxo)
+		})
+		runStream(b, prefix+"/3-BatchPointers", len(ptrs), func() int64 {
+			return streamBatches(ptrs, c.buffer, c.consumers, func(e **Utxo) int64 { return readUtxo(*e) })
+		})
+	})
+}
+
+// BenchmarkTxOut_Channel benchmarks streaming chanItems TxOuts from one
+// producer to several consumers by value and by pointer.
+func BenchmarkTxOut_Channel(b *testing.B) {
+	streamSuite(b, txoutSuite, txoutParams{numTxOuts: chanItems, scriptSize: 34}, nil)
+}
+
+// BenchmarkMsgTx_Channel benchmarks streaming chanItems 2x2 MsgTxs from one
+// producer to several consumers by value and by pointer.
+func BenchmarkMsgTx_Channel(b *testing.B) {
+	streamSuite(b, msgtxSuite, msgtxParams{numTxs: chanItems, scriptSize: 34, nInputs: 2, nOutputs: 2}, nil)
+}
@@ -0,0 +1,254 @@
+package pv
+
+import (
+	"cmp"
+	"math/rand/v2"
+	"slices"
+	"testing"
+
+	"github.com/btcsuite/btcd/btcutil"
+)
+
+// coinStrategy is how a coin selection picks candidates once it has them.
+type coinStrategy int
+
+const (
+	// coinLargestFirst sorts candidates by Amount, largest first, and takes
+	// them until the target is met.
+	coinLargestFirst coinStrategy = iota
+
+	// coinRandom shuffles candidates and takes them until the target is
+	// met, like single random draw.
+	coinRandom
+
+	// coinBranchAndBound sorts candidates by Amount and searches for a set
+	// that lands within coinSelectWindow of the target, falling back to
+	// largest-first when there is none.
+	coinBranchAndBound
+)
+
+const (
+	// coinSelectWindow is how far above the target a branch-and-bound
+	// selection may land, standing in for the cost of a change output.
+	coinSelectWindow btcutil.Amount = 500
+
+	// bnbMaxTries bounds the branch-and-bound search, as Bitcoin Core does.
+	bnbMaxTries = 100000
+)
+
+// coinSelectUtxos returns buildUtxoValues and buildUtxoPointers output with
+// every tenth Utxo, from index 4, locked. makeUtxoValue leaves odd indices
+// unspendable, so 40% of the set are candidates.
+func coinSelectUtxos(p utxoParams) ([]Utxo, []*Utxo) {
+	pkScript := makePkScript(p.scriptSize)
+	vals := buildUtxoValues(p.numUtxos, pkScript)
+	ptrs := buildUtxoPointers(p.numUtxos, pkScript)
+	for i := range vals {
+		vals[i].Locked = i%10 == 4
+		ptrs[i].Locked = i%10 == 4
+	}
+	return vals, ptrs
+}
+
+// coinSelectTarget is a tenth of the candidates' total, so the number of
+// coins a selection takes grows with the set.
+func coinSelectTarget(vals []Utxo) btcutil.Amount {
+	var total btcutil.Amount
+	for i := range vals {
+		if vals[i].Spendable && !vals[i].Locked {
+			total += vals[i].Amount
+		}
+	}
+	return total / 10
+}
+
+// coinLayout is one way of holding the candidates of a selection: the
+// elements themselves, pointers to them or indices into the source slice.
+type coinLayout[T any] struct {
+	// candidates returns a new slice of the spendable, unlocked Utxos.
+	candidates func() []T
+	amount     func(c T) btcutil.Amount
+}
+
+// selectCoins runs strategy s over cands, reordering it, and returns the
+// number of coins taken and their total.
+func selectCoins[T any](s coinStrategy, cands []T, amount func(c T) btcutil.Amount,
+	target btcutil.Amount, rng *rand.Rand) (int, btcutil.Amount) {
+
+	switch s {
+	case coinRandom:
+		rng.Shuffle(len(cands), func(i, j int) { cands[i], cands[j] = cands[j], cands[i] })
+		return takeCoins(cands, amount, target)
+	case coinBranchAndBound:
+		sortCoins(cands, amount)
+		if n, total, ok := branchAndBound(cands, amount, target, coinSelectWindow); ok {
+			return n, total
+		}
+		return takeCoins(cands, amount, target)
+	default:
+		sortCoins(cands, amount)
+		return takeCoins(cands, amount, target)
+	}
+}
+
+// sortCoins sorts cands by Amount, largest first.
+func sortCoins[T any](cands []T, amount func(c T) btcutil.Amount) {
+	slices.SortFunc(cands, func(a, b T) int { return cmp.Compare(amount(b), amount(a)) })
+}
+
+// takeCoins takes cands in order until their total reaches target.
+func takeCoins[T any](cands []T, amount func(c T) btcutil.Amount, target btcutil.Amount) (int, btcutil.Amount) {
+	var total btcutil.Amount
+	for i := range cands {
+		if total >= target {
+			return i, total
+		}
+		total += amount(cands[i])
+	}
+	return len(cands), total
+}
+
+// branchAndBound is the depth-first search of Bitcoin Core's SelectCoinsBnB
+// over cands sorted largest first. It keeps the selection with the least
+// excess over target, rather than the least waste, and returns its size and
+// total, or false when no selection within window exists.
+func branchAndBound[T any](cands []T, amount func(c T) btcutil.Amount,
+	target, window btcutil.Amount) (int, btcutil.Amount, bool) {
+
+	var available btcutil.Amount
+	for i := range cands {
+		available += amount(cands[i])
+	}
+	if available < target {
+		return 0, 0, false
+	}
+
+	var (
+		sel, best []int
+		value     btcutil.Amount
+		bestValue btcutil.Amount = -1
+	)
+	for try, i := 0, 0; try < bnbMaxTries; try, i = try+1, i+1 {
+		backtrack := false
+		switch {
+		case value+available < target, value > target+window:
+			backtrack = true
+		case value >= target:
+			if bestValue < 0 || value < bestValue {
+				best = append(best[:0], sel...)
+				bestValue = value
+			}
+			backtrack = true
+		}
+
+		if !backtrack {
+			a := amount(cands[i])
+			available -= a
+			// Skip a candidate equal to an excluded predecessor: it would
+			// only repeat that branch.
+			if len(sel) == 0 || i-1 == sel[len(sel)-1] || a != amount(cands[i-1]) {
+				sel = append(sel, i)
+				value += a
+			}
+			continue
+		}
+		if len(sel) == 0 {
+			break
+		}
+		// Give back the candidates skipped since the last inclusion, then
+		// take the branch that excludes it.
+		for i--; i > sel[len(sel)-1]; i-- {
+			available += amount(cands[i])
+		}
+		value -= amount(cands[i])
+		sel = sel[:len(sel)-1]
+	}
+	if bestValue < 0 {
+		return 0, 0, false
+	}
+	return len(best), bestValue, true
+}
+
+// runCoinSelect registers one layout's subject. Each op is one selection:
+// gathering the candidates into a new slice, then the strategy. The random
+// strategy is reseeded every op so each draws the same coins.
+func runCoinSelect[T any](b *testing.B, name string, s coinStrategy, target btcutil.Amount, l coinLayout[T]) {
+	b.Run(name, func(b *testing.B) {
+		b.ReportAllocs()
+		pcg := rand.NewPCG(1, 2)
+		rng := rand.New(pcg)
+		m := startGCMeter()
+		for b.Loop() {
+			pcg.Seed(1, 2)
+			n, total := selectCoins(s, l.candidates(), l.amount, target, rng)
+			sinkI64 = int64(total) + int64(n)
+		}
+		m.report(b)
+		b.ReportMetric(float64(b.N)/b.Elapsed().Seconds(), "selections/s")
+	})
+}
+
+// benchCoinSelect runs strategy s over the Utxo datasets, with the
+// candidates as a []Utxo, a []*Utxo and a []int32 of indices into the
+// []Utxo.
+func benchCoinSelect(b *testing.B, s coinStrategy) {
+	for _, d := range utxoDatasets() {
+		vals, ptrs := coinSelectUtxos(d.p)
+		target := coinSelectTarget(vals)
+		prefix := d.name()
+		runCoinSelect(b, prefix+"/0-Values", s, target, coinLayout[Utxo]{
+			candidates: func() []Utxo {
+				cands := make([]Utxo, 0, len(vals))
+				for i := range vals {
+					if vals[i].Spendable && !vals[i].Locked {
+						cands = append(cands, vals[i])
+					}
+				}
+				return cands
+			},
+			amount: func(c Utxo) btcutil.Amount { return c.Amount },
+		})
+		runCoinSelect(b, prefix+"/1-Pointers", s, target, coinLayout[*Utxo]{
+			candidates: func() []*Utxo {
+				cands := make([]*Utxo, 0, len(ptrs))
+				for _, u := range ptrs {
+					if u.Spendable && !u.Locked {
+						cands = append(cands, u)
+					}
+				}
+				return cands
+			},
+			amount: func(c *Utxo) btcutil.Amount { return c.Amount },
+		})
+		runCoinSelect(b, prefix+"/2-Index", s, target, coinLayout[int32]{
+			candidates: func() []int32 {
+				cands := make([]int32, 0, len(vals))
+				for i := range vals {
+					if vals[i].Spendable && !vals[i].Locked {
+						cands = append(cands, int32(i))
+					}
+				}
+				return cands
+			},
+			amount: func(c int32) btcutil.Amount { return vals[c].Amount },
+		})
+	}
+}
+
+// BenchmarkUtxo_CoinSelectLargestFirst benchmarks largest-first coin
+// selection over Utxo values, pointers and an index slice.
+func BenchmarkUtxo_CoinSelectLargestFirst(b *testing.B) {
+	benchCoinSelect(b, coinLargestFirst)
+}
+
+// BenchmarkUtxo_CoinSelectRandom benchmarks random-draw coin selection over
+// Utxo values, pointers and an index slice.
+func BenchmarkUtxo_CoinSelectRandom(b *testing.B) {
+	benchCoinSelect(b, coinRandom)
+}
+
+// BenchmarkUtxo_CoinSelectBnB benchmarks branch-and-bound coin selection
+// over Utxo values, pointers and an index slice.
+func BenchmarkUtxo_CoinSelectBnB(b *testing.B) {
+	benchCoinSelect(b, coinBranchAndBound)
+}
@@ -0,0 +1,205 @@
+package pv
+
+import (
+	"iter"
+	"testing"
+
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/btcsuite/btcd/wire"
+)
+
+// The ListUnspent filter: the account, and the confirmation range of a
+// default listunspent call.
+const (
+	filterAccount = "default"
+	filterMinConf = 1
+	filterMaxConf = 9999999
+)
+
+// unspentMatch reports whether u passes the ListUnspent filter.
+func unspentMatch(u *Utxo) bool {
+	return u.Account == filterAccount && u.Spendable && !u.Locked &&
+		u.Confirmations >= filterMinConf && u.Confirmations <= filterMaxConf
+}
+
+// unspentResult is the projection the filter returns for each match.
+type unspentResult struct {
+	OutPoint      wire.OutPoint
+	Amount        btcutil.Amount
+	Confirmations int32
+}
+
+func projectUnspent(u *Utxo) unspentResult {
+	return unspentResult{OutPoint: u.OutPoint, Amount: u.Amount, Confirmations: u.Confirmations}
+}
+
+// markSelected sets the filtered fields of Utxo i so that pct percent of
+// elements, scattered through the set, pass unspentMatch. Every other
+// element fails exactly one criterion, taken in rotation, so no single
+// field check rejects them all.
+func markSelected(u *Utxo, i, pct int) {
+	u.Account = filterAccount
+	u.Spendable = true
+	u.Locked = false
+	u.Confirmations = int32(filterMinConf + i%100)
+	if scatter(i, 100) < pct {
+		return
+	}
+	switch i % 4 {
+	case 0:
+		u.Account = "imported"
+	case 1:
+		u.Spendable = false
+	case 2:
+		u.Locked = true
+	default:
+		u.Confirmations = 0
+	}
+}
+
+// filterStyle is the shape the filter hands its matches to the projection
+// in.
+type filterStyle int
+
+const (
+	// filterCompact moves matches to the front of the source slice. It
+	// destroys the source, so each op first restores it with a copy.
+	filterCompact filterStyle = iota
+
+	// filterNewValues copies matches into a new []Utxo.
+	filterNewValues
+
+	// filterNewPointers collects pointers to matches in a new []*Utxo.
+	filterNewPointers
+
+	// filterLazy ranges over an iter.Seq[*Utxo] that yields matches as
+	// it finds them.
+	filterLazy
+)
+
+var filterStyleNames = [...]string{
+	filterCompact:     "Compact",
+	filterNewValues:   "NewValues",
+	filterNewPointers: "NewPointers",
+	filterLazy:        "Seq",
+}
+
+// filterSeq returns a lazy filter over s, whose element i is elem(s, i).
+func filterSeq[T any](s []T, elem func(s []T, i int) *Utxo) iter.Seq[*Utxo] {
+	return func(yield func(*Utxo) bool) {
+		for i := range s {
+			if u := elem(s, i); unspentMatch(u) && !yield(u) {
+				return
+			}
+		}
+	}
+}
+
+// runFilter registers one subject of the filter benchmark: src filtered in
+// the given style, then every match projected into a new []unspentResult.
+// Values and pointers reach each Utxo through the same elem func value.
+func runFilter[T any](b *testing.B, name string, src []T, elem func(s []T, i int) *Utxo, style filterStyle) {
+	b.Run(name, func(b *testing.B) {
+		b.ReportAllocs()
+		work := make([]T, len(src))
+		m := startGCMeter()
+		for b.Loop() {
+			var res []unspentResult
+			switch style {
+			case filterCompact:
+				copy(work, src)
+				k := 0
+				for i := range work {
+					if unspentMatch(elem(work, i)) {
+						work[k] = work[i]
+						k++
+					}
+				}
+				for i := range k {
+					res = append(res, projectUnspent(elem(work, i)))
+				}
+			case filterNewValues:
+				var out []Utxo
+				for i := range src {
+					if u := elem(src, i); unspentMatch(u) {
+						out = append(out, *u)
+					}
+				}
+				for i := range out {
+					res = append(res, projectUnspent(&out[i]))
+				}
+			case filterNewPointers:
+				var out []*Utxo
+				for i := range src {
+					if u := elem(src, i); unspentMatch(u) {
+						out = append(out, u)
+					}
+				}
+				for _, u := range out {
+					res = append(res, projectUnspent(u))
+				}
+			case filterLazy:
+				for u := range filterSeq(src, elem) {
+					res = append(res, projectUnspent(u))
+				}
+			}
+			sinkInt = len(res)
+		}
+		m.report(b)
+	})
+}
+
+type filterParams struct {
+	numUtxos int
+
+	// pct is the percentage of Utxos that pass the filter.
+	pct int
+}
+
+// filterSelectivities are the percentages the filter benchmark sweeps.
+var filterSelectivities = []int{1, 10, 50, 100}
+
+// filterDims names the Utxo count and selectivity dimensions.
+// Example prefix: "04096-Utxos-010-PctSelected".
+func filterDims(utxoGrowth growthFunc) []dimension[filterParams] {
+	return []dimension[filterParams]{
+		{label: "Utxos", growth: utxoGrowth, set: func(p *filterParams, v int) { p.numUtxos = v }},
+		{
+			label:  "PctSelected",
+			growth: func(i int) int { return filterSelectivities[i] },
+			set:    func(p *filterParams, v int) { p.pct = v },
+			steps:  len(filterSelectivities),
+		},
+	}
+}
+
+// filterSweep is every selectivity at 1024, 4096 and 16384 Utxos, large
+// enough that 1% still selects several.
+func filterSweep() []dataset[filterParams] {
+	return generateSweep(sweepConfig[filterParams]{
+		dims:       filterDims(func(i int) int { return 1024 << (2 * i) }),
+		iterations: 3,
+	})
+}
+
+// BenchmarkUtxo_FilterProject benchmarks the ListUnspent filter and
+// projection over buildUtxoValues and buildUtxoPointers output, in every
+// filter style, grouped by selectivity so each Utxo count draws its own curve.
+// Example: "010-PctSelected/04096-Utxos/1-Pointers-Seq".
+func BenchmarkUtxo_FilterProject(b *testing.B) {
+	pkScript := makePkScript(34)
+	runSweep(b, filterSweep(), "PctSelected", func(b *testing.B, d dataset[filterParams], prefix string) {
+		vals := buildUtxoValues(d.p.numUtxos, pkScript)
+		ptrs := buildUtxoPointers(d.p.numUtxos, pkScript)
+		for i := range vals {
+			markSelected(&vals[i], i, d.p.pct)
+			markSelected(ptrs[i], i, d.p.pct)
+		}
+		for style, name := range filterStyleNames {
+			runFilter(b, prefix+"/0-Values-"+name, vals, func(s []Utxo, i int) *Utxo { return &s[i] }, filterStyle(style))
+		}
+		for style, name := range filterStyleNames {
+			runFilter(b, prefix+"/1-Pointers-"+name, ptrs, func(s []*Utxo, i int) *Utxo { return s[i] }, filterStyle(style))
+		}
+	})
+}
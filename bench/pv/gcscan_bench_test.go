@@ -0,0 +1,107 @@
+package pv
+
+import (
+	"runtime"
+	"runtime/debug"
+	"testing"
+)
+
+// Allocation churn run by every BenchmarkUtxo_LiveHeapGC iteration: 1024
+// short-lived 128-byte objects, 128 KiB per op.
+const (
+	churnObjects = 1024
+	churnSize    = 128
+)
+
+// churnRing keeps the most recent churn objects reachable so the compiler
+// cannot stack-allocate them.
+var churnRing [64][]byte
+
+func churn(n, size int) {
+	for i := 0; i < n; i++ {
+		churnRing[i%len(churnRing)] = make([]byte, size)
+	}
+}
+
+// gcSetting is a GOGC/GOMEMLIMIT combination applied for one run.
+type gcSetting struct {
+	name string
+
+	// gogc is passed to debug.SetGCPercent; -1 turns the pacer off.
+	gogc int
+
+	// limit returns the memory limit for a given live heap. Nil leaves the
+	// limit unset.
+	limit func(live uint64) int64
+}
+
+// apply installs the setting and returns a func that restores the previous
+// one.
+func (s gcSetting) apply(live uint64) (restore func()) {
+	prevGC := debug.SetGCPercent(s.gogc)
+	prevLimit := debug.SetMemoryLimit(-1)
+	if s.limit != nil {
+		debug.SetMemoryLimit(s.limit(live))
+	}
+	return func() {
+		debug.SetGCPercent(prevGC)
+		debug.SetMemoryLimit(prevLimit)
+	}
+}
+
+var liveHeapGCSettings = []gcSetting{
+	{name: "GOGC-100", gogc: 100},
+	{name: "GOGC-50", gogc: 50},
+	{name: "GOGC-400", gogc: 400},
+	{
+		name:  "GOGC-off-GOMEMLIMIT-live+64MiB",
+		gogc:  -1,
+		limit: func(live uint64) int64 { return int64(live) + 64<<20 },
+	},
+}
+
+// runLiveHeap times the churn workload while resident stays reachable. Beyond
+// the gcMeter metrics it reports gc-mark-ns/op, the mark-phase CPU time per
+// op, and live-B, the live heap the collector has to scan.
+func runLiveHeap(b *testing.B, gc gcSetting, resident any) {
+	live := heapLiveBytes()
+	defer gc.apply(live)()
+	b.ReportAllocs()
+	m := startGCMeter()
+	mark := gcMarkSeconds()
+	for b.Loop() {
+		churn(churnObjects, churnSize)
+	}
+	m.report(b)
+	b.ReportMetric((gcMarkSeconds()-mark)*1e9/float64(b.N), "gc-mark-ns/op")
+	b.ReportMetric(float64(live), "live-B")
+	runtime.KeepAlive(resident)
+}
+
+// BenchmarkUtxo_LiveHeapGC measures what a resident UTXO set costs unrelated
+// code. Each run keeps 1k to 1M Utxos alive, as a []Utxo or a []*Utxo, while
+// a fixed allocation workload triggers collections. The values layout gives
+// the GC one large object to scan; the pointer layout gives it one object per
+// element. 2-NoHeap runs the workload with nothing resident, so the slowdown
+// of the other subjects is their ns/op over it.
+func BenchmarkUtxo_LiveHeapGC(b *testing.B) {
+	datasets := generateDatasets(benchConfig[utxoParams]{
+		dims:       utxoDims(func(i int) int { return 1024 << (2 * i) }, constantGrowth(34)),
+		iterations: 6,
+	})
+	for _, d := range datasets {
+		pkScript := makePkScript(d.p.scriptSize)
+		for _, gc := range liveHeapGCSettings {
+			prefix := d.name() + "-" + gc.name
+			b.Run(prefix+"/0-Values", func(b *testing.B) {
+				runLiveHeap(b, gc, buildUtxoValues(d.p.numUtxos, pkScript))
+			})
+			b.Run(prefix+"/1-Pointers", func(b *testing.B) {
+				runLiveHeap(b, gc, buildUtxoPointers(d.p.numUtxos, pkScript))
+			})
+			b.Run(prefix+"/2-NoHeap", func(b *testing.B) {
+				runLiveHeap(b, gc, nil)
+			})
+		}
+	}
+}
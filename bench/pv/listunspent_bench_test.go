@@ -0,0 +1,88 @@
+package pv
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"testing"
+
+	"github.com/btcsuite/btcd/btcjson"
+)
+
+// toListUnspentResult converts u to its listunspent RPC form, as the wallet
+// server does.
+func toListUnspentResult(u *Utxo) btcjson.ListUnspentResult {
+	var addr string
+	if u.Address != nil {
+		addr = u.Address.EncodeAddress()
+	}
+	return btcjson.ListUnspentResult{
+		TxID:          u.OutPoint.Hash.String(),
+		Vout:          u.OutPoint.Index,
+		Address:       addr,
+		Account:       u.Account,
+		ScriptPubKey:  hex.EncodeToString(u.PkScript),
+		Amount:        u.Amount.ToBTC(),
+		Confirmations: int64(u.Confirmations),
+		Spendable:     u.Spendable,
+	}
+}
+
+// runListUnspent registers the "0-Values" and "1-Pointers" subjects of a
+// listunspent pipeline over one Utxo set. Each op converts the whole set
+// into a fresh []btcjson.ListUnspentResult and, if marshal is set, encodes
+// it with json.Marshal.
+func runListUnspent(b *testing.B, prefix string, p utxoParams, marshal bool) {
+	pkScript := makePkScript(p.scriptSize)
+	vals := buildUtxoValues(p.numUtxos, pkScript)
+	ptrs := buildUtxoPointers(p.numUtxos, pkScript)
+	encode := func(b *testing.B, results []btcjson.ListUnspentResult) int {
+		if !marshal {
+			return len(results)
+		}
+		out, err := json.Marshal(results)
+		if err != nil {
+			b.Fatal(err)
+		}
+		return len(out)
+	}
+	b.Run(prefix+"/0-Values", func(b *testing.B) {
+		b.ReportAllocs()
+		m := startGCMeter()
+		for b.Loop() {
+			results := make([]btcjson.ListUnspentResult, len(vals))
+			for i := range vals {
+				results[i] = toListUnspentResult(&vals[i])
+			}
+			sinkInt = encode(b, results)
+		}
+		m.report(b)
+	})
+	b.Run(prefix+"/1-Pointers", func(b *testing.B) {
+		b.ReportAllocs()
+		m := startGCMeter()
+		for b.Loop() {
+			results := make([]btcjson.ListUnspentResult, len(ptrs))
+			for i, u := range ptrs {
+				results[i] = toListUnspentResult(u)
+			}
+			sinkInt = encode(b, results)
+		}
+		m.report(b)
+	})
+}
+
+// BenchmarkUtxo_ListUnspentConvert benchmarks converting Utxo values vs
+// pointers to []btcjson.ListUnspentResult, without encoding.
+func BenchmarkUtxo_ListUnspentConvert(b *testing.B) {
+	for _, d := range utxoDatasets() {
+		runListUnspent(b, d.name(), d.p, false)
+	}
+}
+
+// BenchmarkUtxo_ListUnspentMarshal benchmarks the full listunspent response
+// path from Utxo values vs pointers: conversion then json.Marshal.
+func BenchmarkUtxo_ListUnspentMarshal(b *testing.B) {
+	for _, d := range utxoDatasets() {
+		runListUnspent(b, d.name(), d.p, true)
+	}
+}
@@ -0,0 +1,114 @@
+package pv
+
+import (
+	"math"
+	"runtime"
+	"runtime/metrics"
+	"testing"
+)
+
+// runtime/metrics sampled around each benchmark run. The pause histogram
+// replaces the deprecated /gc/pauses:seconds.
+const (
+	metricGCCycles    = "/gc/cycles/total:gc-cycles"
+	metricGCPauses    = "/sched/pauses/total/gc:seconds"
+	metricHeapObjects = "/gc/heap/objects:objects"
+)
+
+// gcMeter records GC cycles and stop-the-world pause time at the start of a
+// timed loop. report turns the deltas into custom metrics that benchstat and
+// vizb pick up like ns/op. Together with heap-objs from retainedObjects these
+// are:
+//   - gcs/op: completed GC cycles per iteration.
+//   - gc-pause-ns/op: GC stop-the-world pause time per iteration.
+//   - heap-objs: heap objects left live by one build of the dataset.
+type gcMeter struct {
+	cycles uint64
+	pause  float64
+}
+
+func startGCMeter() gcMeter {
+	cycles, pause := readGCMetrics()
+	return gcMeter{cycles: cycles, pause: pause}
+}
+
+// report must run before retainedObjects, whose forced collections would
+// otherwise be counted.
+func (m gcMeter) report(b *testing.B) {
+	cycles, pause := readGCMetrics()
+	n := float64(b.N)
+	b.ReportMetric(float64(cycles-m.cycles)/n, "gcs/op")
+	b.ReportMetric((pause-m.pause)*1e9/n, "gc-pause-ns/op")
+}
+
+// readGCMetrics returns the completed GC cycle count and the total GC pause
+// time in seconds.
+func readGCMetrics() (cycles uint64, pause float64) {
+	s := []metrics.Sample{{Name: metricGCCycles}, {Name: metricGCPauses}}
+	metrics.Read(s)
+	return s[0].Value.Uint64(), histogramSum(s[1].Value.Float64Histogram())
+}
+
+// histogramSum approximates the total of a runtime/metrics histogram by
+// counting every sample at its bucket midpoint. Unbounded buckets use their
+// finite edge.
+func histogramSum(h *metrics.Float64Histogram) float64 {
+	var sum float64
+	for i, c := range h.Counts {
+		if c == 0 {
+			continue
+		}
+		lo, hi := h.Buckets[i], h.Buckets[i+1]
+		switch {
+		case math.IsInf(lo, -1):
+			lo = hi
+		case math.IsInf(hi, 1):
+			hi = lo
+		}
+		sum += float64(c) * (lo + hi) / 2
+	}
+	return sum
+}
+
+// heapObjects forces a collection and returns the number of live heap
+// objects.
+func heapObjects() uint64 {
+	runtime.GC()
+	s := []metrics.Sample{{Name: metricHeapObjects}}
+	metrics.Read(s)
+	return s[0].Value.Uint64()
+}
+
+// retainedObjects runs build outside the timed loop and returns how many
+// heap objects its result keeps live. build must store its result somewhere
+// that outlives the call. Callers report it as heap-objs after the loop,
+// since b.Loop clears metrics reported before it starts.
+func retainedObjects(build func()) float64 {
+	before := heapObjects()
+	build()
+	after := heapObjects()
+	if after < before {
+		return 0
+	}
+	return float64(after - before)
+}
+
+// gcMarkSeconds returns the CPU time spent in the GC mark phase so far,
+// summed over assist, dedicated and idle mark workers.
+func gcMarkSeconds() float64 {
+	s := []metrics.Sample{
+		{Name: "/cpu/classes/gc/mark/assist:cpu-seconds"},
+		{Name: "/cpu/classes/gc/mark/dedicated:cpu-seconds"},
+		{Name: "/cpu/classes/gc/mark/idle:cpu-seconds"},
+	}
+	metrics.Read(s)
+	return s[0].Value.Float64() + s[1].Value.Float64() + s[2].Value.Float64()
+}
+
+// heapLiveBytes forces a collection and returns the heap bytes marked live.
+func heapLiveBytes() uint64 {
+	runtime.GC()
+	s := []metrics.Sample{{Name: "/gc/heap/live:bytes"}}
+	metrics.Read(s)
+	return s[0].Value.Uint64()
+}
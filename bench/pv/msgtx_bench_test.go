@@ -1,17 +1,17 @@
 package pv
 
 import (
+	"bytes"
 	"fmt"
+	"slices"
 	"testing"
 
 	"github.com/btcsuite/btcd/chaincfg/chainhash"
 	"github.com/btcsuite/btcd/wire"
 )
 
-func makeMsgTxValue(i, nIn, nOut, scriptSize int) wire.MsgTx {
-	if scriptSize < 0 {
-		scriptSize = 0
-	}
+func makeMsgTxValue(i, nIn, nOut, scriptSize int, witness witnessShape, scripts *scriptAllocator) wire.MsgTx {
+	sigSize := sigScriptSize(scriptSize, witness)
 	tx := wire.MsgTx{
 		Version:  2,
 		TxIn:     make([]*wire.TxIn, nIn),
@@ -21,24 +21,22 @@ func makeMsgTxValue(i, nIn, nOut, scriptSize int) wire.MsgTx {
 	for j := 0; j < nIn; j++ {
 		tx.TxIn[j] = &wire.TxIn{
 			PreviousOutPoint: wire.OutPoint{Hash: chainhash.Hash{byte((i + j) % 251)}, Index: uint32(i + j)},
-			SignatureScript:  make([]byte, scriptSize),
-			Witness:          nil,
+			SignatureScript:  scripts.next(sigSize),
+			Witness:          makeWitness(witness),
 			Sequence:         uint32(100000 + i + j),
 		}
 	}
 	for k := 0; k < nOut; k++ {
 		tx.TxOut[k] = &wire.TxOut{
 			Value:    int64(1000 + i + k),
-			PkScript: make([]byte, scriptSize),
+			PkScript: scripts.next(scriptSize),
 		}
 	}
 	return tx
 }
 
-func makeMsgTxPointer(i, nIn, nOut, scriptSize int) *wire.MsgTx {
-	if scriptSize < 0 {
-		scriptSize = 0
-	}
+func makeMsgTxPointer(i, nIn, nOut, scriptSize int, witness witnessShape, scripts *scriptAllocator) *wire.MsgTx {
+	sigSize := sigScriptSize(scriptSize, witness)
 	tx := wire.MsgTx{
 		Version:  2,
 		TxIn:     make([]*wire.TxIn, nIn),
@@ -48,173 +46,400 @@ func makeMsgTxPointer(i, nIn, nOut, scriptSize int) *wire.MsgTx {
 	for j := 0; j < nIn; j++ {
 		tx.TxIn[j] = &wire.TxIn{
 			PreviousOutPoint: wire.OutPoint{Hash: chainhash.Hash{byte((i + j) % 251)}, Index: uint32(i + j)},
-			SignatureScript:  make([]byte, scriptSize),
-			Witness:          nil,
+			SignatureScript:  scripts.next(sigSize),
+			Witness:          makeWitness(witness),
 			Sequence:         uint32(100000 + i + j),
 		}
 	}
 	for k := 0; k < nOut; k++ {
 		tx.TxOut[k] = &wire.TxOut{
 			Value:    int64(1000 + i + k),
-			PkScript: make([]byte, scriptSize),
+			PkScript: scripts.next(scriptSize),
 		}
 	}
 	return &tx
 }
 
-func buildMsgTxValues(n, nIn, nOut, scriptSize int) []wire.MsgTx {
+// msgtxScripts starts the script allocation of one build of n nIn x nOut
+// transactions with len(pkScript) byte output scripts.
+func msgtxScripts(n, nIn, nOut int, pkScript []byte, witness witnessShape, alloc scriptAlloc) scriptAllocator {
+	perTx := nIn*sigScriptSize(len(pkScript), witness) + nOut*len(pkScript)
+	return alloc.scripts(pkScript, n*perTx)
+}
+
+// buildMsgTxValues constructs a slice of MsgTx values whose scripts are
+// allocated by alloc.
+func buildMsgTxValues(n, nIn, nOut int, pkScript []byte, witness witnessShape, alloc scriptAlloc) []wire.MsgTx {
+	scripts := msgtxScripts(n, nIn, nOut, pkScript, witness, alloc)
 	s := make([]wire.MsgTx, n)
 	for i := 0; i < n; i++ {
-		s[i] = makeMsgTxValue(i, nIn, nOut, scriptSize)
+		s[i] = makeMsgTxValue(i, nIn, nOut, len(pkScript), witness, &scripts)
+	}
+	return s
+}
+
+// buildMsgTxPointers constructs a slice of MsgTx pointers whose scripts are
+// allocated by alloc.
+func buildMsgTxPointers(n, nIn, nOut int, pkScript []byte, witness witnessShape, alloc scriptAlloc) []*wire.MsgTx {
+	scripts := msgtxScripts(n, nIn, nOut, pkScript, witness, alloc)
+	s := make([]*wire.MsgTx, n)
+	for i := 0; i < n; i++ {
+		s[i] = makeMsgTxPointer(i, nIn, nOut, len(pkScript), witness, &scripts)
+	}
+	return s
+}
+
+// buildMsgTxValuesScripts constructs a slice of MsgTx values with nOut
+// outputs each, whose pkScripts are taken in order from scripts. Each output
+// gets its own copy of its script; inputs carry no signature script.
+func buildMsgTxValuesScripts(nIn, nOut int, scripts [][]byte) []wire.MsgTx {
+	sigScripts := allocPerElement.scripts(nil, 0)
+	s := make([]wire.MsgTx, len(scripts)/nOut)
+	for i := range s {
+		s[i] = makeMsgTxValue(i, nIn, nOut, 0, witnessNone, &sigScripts)
+		setMsgTxScripts(&s[i], scripts[i*nOut:])
 	}
 	return s
 }
 
-func buildMsgTxPointers(n, nIn, nOut, scriptSize int) []*wire.MsgTx {
+// buildMsgTxPointersScripts is buildMsgTxValuesScripts for MsgTx pointers.
+func buildMsgTxPointersScripts(nIn, nOut int, scripts [][]byte) []*wire.MsgTx {
+	sigScripts := allocPerElement.scripts(nil, 0)
+	s := make([]*wire.MsgTx, len(scripts)/nOut)
+	for i := range s {
+		s[i] = makeMsgTxPointer(i, nIn, nOut, 0, witnessNone, &sigScripts)
+		setMsgTxScripts(s[i], scripts[i*nOut:])
+	}
+	return s
+}
+
+// setMsgTxScripts gives every output of tx a copy of the next script.
+func setMsgTxScripts(tx *wire.MsgTx, scripts [][]byte) {
+	for k, to := range tx.TxOut {
+		to.PkScript = bytes.Clone(scripts[k])
+	}
+}
+
+// buildMsgTxSlab constructs a slice of MsgTx pointers whose transactions,
+// inputs, outputs and TxIn/TxOut pointer slices are all carved out of a few
+// pre-allocated blocks. Scripts are allocated by alloc and witness items per
+// element, as in buildMsgTxPointers.
+func buildMsgTxSlab(n, nIn, nOut int, pkScript []byte, witness witnessShape, alloc scriptAlloc) []*wire.MsgTx {
+	scripts := msgtxScripts(n, nIn, nOut, pkScript, witness, alloc)
+	sigSize := sigScriptSize(len(pkScript), witness)
+	txs := make([]wire.MsgTx, n)
+	ins := make([]wire.TxIn, n*nIn)
+	outs := make([]wire.TxOut, n*nOut)
+	inPtrs := make([]*wire.TxIn, n*nIn)
+	outPtrs := make([]*wire.TxOut, n*nOut)
 	s := make([]*wire.MsgTx, n)
 	for i := 0; i < n; i++ {
-		s[i] = makeMsgTxPointer(i, nIn, nOut, scriptSize)
+		tx := &txs[i]
+		tx.Version = 2
+		tx.TxIn = inPtrs[i*nIn : (i+1)*nIn : (i+1)*nIn]
+		tx.TxOut = outPtrs[i*nOut : (i+1)*nOut : (i+1)*nOut]
+		for j := 0; j < nIn; j++ {
+			ti := &ins[i*nIn+j]
+			ti.PreviousOutPoint = wire.OutPoint{Hash: chainhash.Hash{byte((i + j) % 251)}, Index: uint32(i + j)}
+			ti.SignatureScript = scripts.next(sigSize)
+			ti.Witness = makeWitness(witness)
+			ti.Sequence = uint32(100000 + i + j)
+			tx.TxIn[j] = ti
+		}
+		for k := 0; k < nOut; k++ {
+			to := &outs[i*nOut+k]
+			to.Value = int64(1000 + i + k)
+			to.PkScript = scripts.next(len(pkScript))
+			tx.TxOut[k] = to
+		}
+		s[i] = tx
 	}
 	return s
 }
 
+// readMsgTx is the accessor summed by every MsgTx iterate benchmark.
+func readMsgTx(tx *wire.MsgTx) int64 {
+	// Sum across inputs and outputs to exercise nested fields.
+	var acc int64
+	for _, ti := range tx.TxIn {
+		acc += int64(len(ti.SignatureScript))
+		acc += int64(ti.Sequence)
+		acc += int64(ti.PreviousOutPoint.Index)
+		acc += int64(ti.PreviousOutPoint.Hash[0])
+		acc += readWitness(ti.Witness)
+	}
+	for _, to := range tx.TxOut {
+		acc += to.Value + int64(len(to.PkScript))
+	}
+	return acc
+}
+
+// sumMsgTxValues is readMsgTx over a []wire.MsgTx.
+func sumMsgTxValues(vals []wire.MsgTx) int64 {
+	var acc int64
+	for _, val := range vals {
+		acc += readMsgTx(&val)
+	}
+	return acc
+}
+
+// sumMsgTxPointers is readMsgTx over a []*wire.MsgTx.
+func sumMsgTxPointers(ptrs []*wire.MsgTx) int64 {
+	var acc int64
+	for _, ptr := range ptrs {
+		acc += readMsgTx(ptr)
+	}
+	return acc
+}
+
+type msgtxParams struct {
+	numTxs     int
+	scriptSize int
+	nInputs    int
+	nOutputs   int
+	witness    witnessShape
+
+	// mix selects generated output scripts for the script type datasets;
+	// scriptSize is unused there.
+	mix scriptMix
+
+	// alloc is how scripts are allocated. The default makes one per input
+	// and output.
+	alloc scriptAlloc
+}
+
+// msgtxDims names the MsgTx count, script size and per-tx input/output
+// dimensions. Example prefix: "04096-Txs-0034-Script-2x2" where 2x2 are
+// in/out counts.
+func msgtxDims(txGrowth, scriptGrowth, inputGrowth, outputGrowth growthFunc) []dimension[msgtxParams] {
+	return []dimension[msgtxParams]{
+		{label: "Txs", growth: txGrowth, set: func(p *msgtxParams, v int) { p.numTxs = v }},
+		{label: "Script", growth: scriptGrowth, set: func(p *msgtxParams, v int) { p.scriptSize = v }},
+		{
+			label:  "Inputs",
+			growth: inputGrowth,
+			set:    func(p *msgtxParams, v int) { p.nInputs = v },
+			format: func(v, _ int) string { return fmt.Sprintf("-%d", v) },
+		},
+		{
+			label:  "Outputs",
+			growth: outputGrowth,
+			set:    func(p *msgtxParams, v int) { p.nOutputs = v },
+			format: func(v, _ int) string { return fmt.Sprintf("x%d", v) },
+		},
+	}
+}
+
+// msgtxWitnessDims is msgtxDims with a witness dimension after the script
+// size, which then sizes only the output scripts. Example prefix:
+// "04096-Txs-0034-Script-P2WPKH-Witness-2x2".
+func msgtxWitnessDims(txGrowth, scriptGrowth, inputGrowth, outputGrowth growthFunc) []dimension[msgtxParams] {
+	dims := msgtxDims(txGrowth, scriptGrowth, inputGrowth, outputGrowth)
+	return slices.Insert(dims, 2, witnessDim(func(p *msgtxParams, w witnessShape) { p.witness = w }))
+}
+
+// msgtxWitnessGrowthDims is msgtxDims with the witness item count and size
+// dimensions after the script size. Example prefix:
+// "256-Txs-34-Script-4-WitnessItems-128-ItemBytes-2x2".
+func msgtxWitnessGrowthDims(txGrowth, scriptGrowth, countGrowth, sizeGrowth, inputGrowth, outputGrowth growthFunc) []dimension[msgtxParams] {
+	dims := msgtxDims(txGrowth, scriptGrowth, inputGrowth, outputGrowth)
+	return slices.Insert(dims, 2, witnessGrowthDims(func(p *msgtxParams) *witnessShape { return &p.witness }, countGrowth, sizeGrowth)...)
+}
+
+// msgtxAllocDims is msgtxDims with a script allocation dimension after the
+// script size. Example prefix: "04096-Txs-0034-Script-Arena-2x2".
+func msgtxAllocDims(txGrowth, scriptGrowth, inputGrowth, outputGrowth growthFunc) []dimension[msgtxParams] {
+	dims := msgtxDims(txGrowth, scriptGrowth, inputGrowth, outputGrowth)
+	return slices.Insert(dims, 2, allocDim(func(p *msgtxParams, a scriptAlloc) { p.alloc = a }))
+}
+
+// msgtxScriptDims names the MsgTx count, script type and per-tx input/output
+// dimensions. Example prefix: "256-Txs-P2TR-2x2".
+func msgtxScriptDims(txGrowth, inputGrowth, outputGrowth growthFunc) []dimension[msgtxParams] {
+	dims := msgtxDims(txGrowth, constantGrowth(0), inputGrowth, outputGrowth)
+	dims[1] = scriptDim(func(p *msgtxParams, m scriptMix) { p.mix = m })
+	return dims
+}
+
+// msgtxBuilders are the value and pointer builders shared by every MsgTx
+// suite.
+func msgtxBuilders(p msgtxParams) (func() []wire.MsgTx, func() []*wire.MsgTx) {
+	pkScript := makePkScript(p.scriptSize)
+	alloc := p.alloc.or(allocPerElement)
+	return func() []wire.MsgTx {
+			return buildMsgTxValues(p.numTxs, p.nInputs, p.nOutputs, pkScript, p.witness, alloc)
+		},
+		func() []*wire.MsgTx {
+			return buildMsgTxPointers(p.numTxs, p.nInputs, p.nOutputs, pkScript, p.witness, alloc)
+		}
+}
+
+// msgtxLayouts returns the extra MsgTx layouts with read as their per-element
+// work, so suites that read differently still compare every layout.
+func msgtxLayouts(read func(tx *wire.MsgTx) int64) []pvLayout[msgtxParams] {
+	return []pvLayout[msgtxParams]{
+		layout[msgtxParams, []*wire.MsgTx]{
+			subject: "3-Slab",
+			builder: func(p msgtxParams) func() []*wire.MsgTx {
+				pkScript := makePkScript(p.scriptSize)
+				alloc := p.alloc.or(allocPerElement)
+				return func() []*wire.MsgTx {
+					return buildMsgTxSlab(p.numTxs, p.nInputs, p.nOutputs, pkScript, p.witness, alloc)
+				}
+			},
+			len:  func(s *[]*wire.MsgTx) int { return len(*s) },
+			read: func(s *[]*wire.MsgTx, i int) int64 { return read((*s)[i]) },
+		},
+	}
+}
+
+var msgtxSuite = pvSuite[msgtxParams, wire.MsgTx]{
+	builders:    msgtxBuilders,
+	read:        readMsgTx,
+	sumValues:   sumMsgTxValues,
+	sumPointers: sumMsgTxPointers,
+	layouts:     msgtxLayouts(readMsgTx),
+}
+
+// msgtxDatasets is the standard lock-step MsgTx progression with 2x2 txs.
+func msgtxDatasets() []dataset[msgtxParams] {
+	return generateDatasets(benchConfig[msgtxParams]{
+		dims:       msgtxDims(scaleGrowth(4, exponentialGrowth()), linearGrowth(34), constantGrowth(2), constantGrowth(2)),
+		iterations: 8,
+	})
+}
+
+// msgtxWitnessDatasets is the grid of 4..512 2x2 txs against every witness
+// preset, with 34-byte output scripts.
+func msgtxWitnessDatasets() []dataset[msgtxParams] {
+	dims := msgtxWitnessDims(scaleGrowth(4, exponentialGrowth()), constantGrowth(34), constantGrowth(2), constantGrowth(2))
+	dims[1].steps, dims[3].steps, dims[4].steps = 1, 1, 1
+	return generateSweep(sweepConfig[msgtxParams]{
+		dims:       dims,
+		iterations: 8,
+	})
+}
+
+// msgtxWitnessGrowthDatasets is the grid of 1..8 witness items against
+// 32..256 byte items, at 256 2x2 txs with 34-byte output scripts.
+func msgtxWitnessGrowthDatasets() []dataset[msgtxParams] {
+	dims := msgtxWitnessGrowthDims(constantGrowth(256), constantGrowth(34), exponentialGrowth(),
+		scaleGrowth(32, exponentialGrowth()), constantGrowth(2), constantGrowth(2))
+	dims[0].steps, dims[1].steps, dims[4].steps, dims[5].steps = 1, 1, 1, 1
+	return generateSweep(sweepConfig[msgtxParams]{
+		dims:       dims,
+		iterations: 4,
+	})
+}
+
+// msgtxAllocDatasets is the grid of 4..512 2x2 txs with 34-byte scripts
+// under every script allocation strategy.
+func msgtxAllocDatasets() []dataset[msgtxParams] {
+	dims := msgtxAllocDims(scaleGrowth(4, exponentialGrowth()), constantGrowth(34), constantGrowth(2), constantGrowth(2))
+	dims[1].steps, dims[3].steps, dims[4].steps = 1, 1, 1
+	return generateSweep(sweepConfig[msgtxParams]{
+		dims:       dims,
+		iterations: 8,
+	})
+}
+
 // BenchmarkMsgTx_SliceBuild benchmarks building slices of MsgTx values vs pointers
 func BenchmarkMsgTx_SliceBuild(b *testing.B) {
-	datasets := generateMsgTxDatasets(msgtxBenchConfig{
-		txGrowth:     scaleGrowth(4, exponentialGrowth()),
-		scriptGrowth: linearGrowth(34),
-		iterations:   8,
-		nInputs:      2,
-		nOutputs:     2,
-	})
-	for _, d := range datasets {
-		prefix := d.name()
-		b.Run(prefix+"/0-Values", func(b *testing.B) {
-			b.ReportAllocs()
-			var s []wire.MsgTx
-			for b.Loop() {
-				s = buildMsgTxValues(d.numTxs, d.nInputs, d.nOutputs, d.scriptSize)
-			}
-			sinkInt = len(s)
-		})
-		b.Run(prefix+"/1-Pointers", func(b *testing.B) {
-			b.ReportAllocs()
-			var s []*wire.MsgTx
-			for b.Loop() {
-				s = buildMsgTxPointers(d.numTxs, d.nInputs, d.nOutputs, d.scriptSize)
-			}
-			sinkInt = len(s)
-		})
-	}
+	msgtxSuite.sliceBuild(b, msgtxDatasets())
 }
 
 // BenchmarkMsgTx_SliceIterate benchmarks iterating over slices of MsgTx values vs pointers
 func BenchmarkMsgTx_SliceIterate(b *testing.B) {
-	datasets := generateMsgTxDatasets(msgtxBenchConfig{
-		txGrowth:     scaleGrowth(4, exponentialGrowth()),
-		scriptGrowth: linearGrowth(34),
-		iterations:   8,
-		nInputs:      2,
-		nOutputs:     2,
+	msgtxSuite.sliceIterate(b, msgtxDatasets())
+}
+
+// BenchmarkMsgTx_SeqIterate benchmarks iter.Seq and iter.Seq2 iterators over
+// slices of MsgTx values vs pointers next to the plain range loops.
+func BenchmarkMsgTx_SeqIterate(b *testing.B) {
+	msgtxSuite.seqIterate(b, msgtxDatasets())
+}
+
+// BenchmarkMsgTx_SliceIterateParallel benchmarks concurrent readers sharing one
+// slice of MsgTx values vs pointers at GOMAXPROCS 1, 2, 4 and 8.
+func BenchmarkMsgTx_SliceIterateParallel(b *testing.B) {
+	msgtxSuite.sliceIterateParallel(b, msgtxDatasets(), parallelProcs)
+}
+
+// BenchmarkMsgTx_WitnessSliceBuild benchmarks building slices of segwit
+// MsgTx values vs pointers, allocating every witness item.
+func BenchmarkMsgTx_WitnessSliceBuild(b *testing.B) {
+	msgtxSuite.sliceBuild(b, msgtxWitnessDatasets())
+}
+
+// BenchmarkMsgTx_WitnessSliceIterate benchmarks iterating over slices of
+// segwit MsgTx values vs pointers, reading every witness item.
+func BenchmarkMsgTx_WitnessSliceIterate(b *testing.B) {
+	msgtxSuite.sliceIterate(b, msgtxWitnessDatasets())
+}
+
+// BenchmarkMsgTx_WitnessGrowthSliceBuild benchmarks building slices of
+// segwit MsgTx values vs pointers as the witness item count and size grow.
+func BenchmarkMsgTx_WitnessGrowthSliceBuild(b *testing.B) {
+	msgtxSuite.sliceBuild(b, msgtxWitnessGrowthDatasets())
+}
+
+// BenchmarkMsgTx_WitnessGrowthSliceIterate benchmarks iterating over slices of
+// segwit MsgTx values vs pointers as the witness item count and size grow.
+func BenchmarkMsgTx_WitnessGrowthSliceIterate(b *testing.B) {
+	msgtxSuite.sliceIterate(b, msgtxWitnessGrowthDatasets())
+}
+
+// BenchmarkMsgTx_ScriptAllocSliceBuild benchmarks building slices of MsgTx
+// values vs pointers with shared, per-element and arena-backed scripts.
+func BenchmarkMsgTx_ScriptAllocSliceBuild(b *testing.B) {
+	msgtxSuite.sliceBuild(b, msgtxAllocDatasets())
+}
+
+// BenchmarkMsgTx_ScriptAllocSliceIterate benchmarks iterating over slices of
+// MsgTx values vs pointers with shared, per-element and arena-backed scripts.
+func BenchmarkMsgTx_ScriptAllocSliceIterate(b *testing.B) {
+	msgtxSuite.sliceIterate(b, msgtxAllocDatasets())
+}
+
+// msgtxScriptSuite builds MsgTxs whose output pkScripts are real standard
+// scripts from the dataset's script mix. The scripts are generated once per
+// dataset; the builders only copy them.
+var msgtxScriptSuite = pvSuite[msgtxParams, wire.MsgTx]{
+	builders: func(p msgtxParams) (func() []wire.MsgTx, func() []*wire.MsgTx) {
+		scripts := p.mix.scripts(p.numTxs * p.nOutputs)
+		return func() []wire.MsgTx { return buildMsgTxValuesScripts(p.nInputs, p.nOutputs, scripts) },
+			func() []*wire.MsgTx { return buildMsgTxPointersScripts(p.nInputs, p.nOutputs, scripts) }
+	},
+	read:        readMsgTx,
+	sumValues:   sumMsgTxValues,
+	sumPointers: sumMsgTxPointers,
+}
+
+// msgtxScriptDatasets is the grid of 4..512 2x2 txs against every script mix.
+func msgtxScriptDatasets() []dataset[msgtxParams] {
+	dims := msgtxScriptDims(scaleGrowth(4, exponentialGrowth()), constantGrowth(2), constantGrowth(2))
+	dims[2].steps, dims[3].steps = 1, 1
+	return generateSweep(sweepConfig[msgtxParams]{
+		dims:       dims,
+		iterations: 8,
 	})
-	for _, d := range datasets {
-		vals := buildMsgTxValues(d.numTxs, d.nInputs, d.nOutputs, d.scriptSize)
-		ptrs := buildMsgTxPointers(d.numTxs, d.nInputs, d.nOutputs, d.scriptSize)
-		prefix := d.name()
-		b.Run(prefix+"/0-Values", func(b *testing.B) {
-			b.ReportAllocs()
-			var acc int64
-			for b.Loop() {
-				for _, tx := range vals {
-					// Sum across inputs and outputs to exercise nested fields.
-					for _, ti := range tx.TxIn {
-						acc += int64(len(ti.SignatureScript))
-						acc += int64(ti.Sequence)
-						acc += int64(ti.PreviousOutPoint.Index)
-						acc += int64(ti.PreviousOutPoint.Hash[0])
-					}
-					for _, to := range tx.TxOut {
-						acc += to.Value + int64(len(to.PkScript))
-					}
-				}
-			}
-			sinkI64 = acc
-		})
-		b.Run(prefix+"/1-Pointers", func(b *testing.B) {
-			b.ReportAllocs()
-			var acc int64
-			for b.Loop() {
-				for _, tx := range ptrs {
-					for _, ti := range tx.TxIn {
-						acc += int64(len(ti.SignatureScript))
-						acc += int64(ti.Sequence)
-						acc += int64(ti.PreviousOutPoint.Index)
-						acc += int64(ti.PreviousOutPoint.Hash[0])
-					}
-					for _, to := range tx.TxOut {
-						acc += to.Value + int64(len(to.PkScript))
-					}
-				}
-			}
-			sinkI64 = acc
-		})
-	}
+}
+
+// BenchmarkMsgTx_ScriptTypeSliceBuild benchmarks building slices of MsgTx
+// values vs pointers whose outputs hold real standard scripts.
+func BenchmarkMsgTx_ScriptTypeSliceBuild(b *testing.B) {
+	msgtxScriptSuite.sliceBuild(b, msgtxScriptDatasets())
+}
+
+// BenchmarkMsgTx_ScriptTypeSliceIterate benchmarks iterating over slices of
+// MsgTx values vs pointers whose outputs hold real standard scripts.
+func BenchmarkMsgTx_ScriptTypeSliceIterate(b *testing.B) {
+	msgtxScriptSuite.sliceIterate(b, msgtxScriptDatasets())
 }
 
 // BenchmarkMsgTx_SliceBuildAndIterate benchmarks building and iterating over slices
 // of MsgTx values vs pointers with repeated reads.
 func BenchmarkMsgTx_SliceBuildAndIterate(b *testing.B) {
-	d := msgtxDataset{
-		numTxs:     256,
-		scriptSize: 64,
-		nInputs:    2,
-		nOutputs:   2,
-		maxTxs:     256,
-		maxScript:  64,
-	}
-	for i := range 10 {
-		prefix := d.name() + fmt.Sprintf("NReads%d", i)
-		b.Run(prefix+"/0-Values", func(b *testing.B) {
-			b.ReportAllocs()
-			var acc int64
-			for b.Loop() {
-				vals := buildMsgTxValues(d.numTxs, d.nInputs, d.nOutputs, d.scriptSize)
-				for range i {
-					for _, tx := range vals {
-						for _, ti := range tx.TxIn {
-							acc += int64(len(ti.SignatureScript))
-							acc += int64(ti.Sequence)
-							acc += int64(ti.PreviousOutPoint.Index)
-							acc += int64(ti.PreviousOutPoint.Hash[0])
-						}
-						for _, to := range tx.TxOut {
-							acc += to.Value + int64(len(to.PkScript))
-						}
-					}
-				}
-			}
-			sinkI64 = acc
-		})
-		b.Run(prefix+"/1-Pointers", func(b *testing.B) {
-			b.ReportAllocs()
-			var acc int64
-			for b.Loop() {
-				ptrs := buildMsgTxPointers(d.numTxs, d.nInputs, d.nOutputs, d.scriptSize)
-				for range i {
-					for _, tx := range ptrs {
-						for _, ti := range tx.TxIn {
-							acc += int64(len(ti.SignatureScript))
-							acc += int64(ti.Sequence)
-							acc += int64(ti.PreviousOutPoint.Index)
-							acc += int64(ti.PreviousOutPoint.Hash[0])
-						}
-						for _, to := range tx.TxOut {
-							acc += to.Value + int64(len(to.PkScript))
-						}
-					}
-				}
-			}
-			sinkI64 = acc
-		})
-	}
+	d := singleDataset(msgtxDims(constantGrowth(256), constantGrowth(64), constantGrowth(2), constantGrowth(2)))
+	msgtxSuite.sliceBuildAndIterate(b, d, 10)
 }
@@ -0,0 +1,193 @@
+package pv
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/btcsuite/btcd/wire"
+)
+
+// serializeMsgTxs returns the wire encoding of every transaction in txs.
+func serializeMsgTxs(txs []wire.MsgTx) [][]byte {
+	raw := make([][]byte, len(txs))
+	for i := range txs {
+		var buf bytes.Buffer
+		buf.Grow(txs[i].SerializeSize())
+		if err := txs[i].Serialize(&buf); err != nil {
+			panic(err)
+		}
+		raw[i] = buf.Bytes()
+	}
+	return raw
+}
+
+// msgtxWireDatasets is the standard MsgTx progression followed by the
+// witness grid, so every wire benchmark covers segwit encodings too.
+func msgtxWireDatasets() []dataset[msgtxParams] {
+	return append(msgtxDatasets(), msgtxWitnessDatasets()...)
+}
+
+// BenchmarkMsgTx_Deserialize benchmarks decoding N raw transactions into a
+// []wire.MsgTx vs a []*wire.MsgTx. The raw bytes are encoded once per dataset
+// and read through one reused bytes.Reader, so each op is the slice, the
+// decoder's allocations and, for pointers, one MsgTx per element.
+func BenchmarkMsgTx_Deserialize(b *testing.B) {
+	for _, d := range msgtxWireDatasets() {
+		values, _ := msgtxBuilders(d.p)
+		raw := serializeMsgTxs(values())
+		prefix := d.name()
+		b.Run(prefix+"/0-Values", func(b *testing.B) {
+			b.ReportAllocs()
+			var r bytes.Reader
+			var txs []wire.MsgTx
+			decode := func() {
+				txs = make([]wire.MsgTx, len(raw))
+				for i := range raw {
+					r.Reset(raw[i])
+					if err := txs[i].Deserialize(&r); err != nil {
+						b.Fatal(err)
+					}
+				}
+			}
+			m := startGCMeter()
+			for b.Loop() {
+				decode()
+			}
+			m.report(b)
+			txs = nil
+			b.ReportMetric(retainedObjects(decode), "heap-objs")
+			sinkInt = len(txs)
+		})
+		b.Run(prefix+"/1-Pointers", func(b *testing.B) {
+			b.ReportAllocs()
+			var r bytes.Reader
+			var txs []*wire.MsgTx
+			decode := func() {
+				txs = make([]*wire.MsgTx, len(raw))
+				for i := range raw {
+					tx := new(wire.MsgTx)
+					r.Reset(raw[i])
+					if err := tx.Deserialize(&r); err != nil {
+						b.Fatal(err)
+					}
+					txs[i] = tx
+				}
+			}
+			m := startGCMeter()
+			for b.Loop() {
+				decode()
+			}
+			m.report(b)
+			txs = nil
+			b.ReportMetric(retainedObjects(decode), "heap-objs")
+			sinkInt = len(txs)
+		})
+	}
+}
+
+// BenchmarkMsgTx_BtcEncode benchmarks encoding every transaction of each
+// MsgTx layout with witness encoding into one reused buffer.
+func BenchmarkMsgTx_BtcEncode(b *testing.B) {
+	var buf bytes.Buffer
+	encode := func(tx *wire.MsgTx) int64 {
+		buf.Reset()
+		if err := tx.BtcEncode(&buf, wire.ProtocolVersion, wire.WitnessEncoding); err != nil {
+			panic(err)
+		}
+		return int64(buf.Len())
+	}
+	s := pvSuite[msgtxParams, wire.MsgTx]{
+		builders: msgtxBuilders,
+		read:     encode,
+		sumValues: func(vals []wire.MsgTx) int64 {
+			var acc int64
+			for _, val := range vals {
+				acc += encode(&val)
+			}
+			return acc
+		},
+		sumPointers: func(ptrs []*wire.MsgTx) int64 {
+			var acc int64
+			for _, ptr := range ptrs {
+				acc += encode(ptr)
+			}
+			return acc
+		},
+		layouts: msgtxLayouts(encode),
+	}
+	s.sliceIterate(b, msgtxWireDatasets())
+}
+
+// hashMsgTx computes the txid of tx.
+func hashMsgTx(tx *wire.MsgTx) int64 {
+	h := tx.TxHash()
+	return int64(h[0])
+}
+
+// sumMsgTxHashValues is hashMsgTx over a []wire.MsgTx.
+func sumMsgTxHashValues(vals []wire.MsgTx) int64 {
+	var acc int64
+	for _, val := range vals {
+		acc += hashMsgTx(&val)
+	}
+	return acc
+}
+
+// sumMsgTxHashPointers is hashMsgTx over a []*wire.MsgTx.
+func sumMsgTxHashPointers(ptrs []*wire.MsgTx) int64 {
+	var acc int64
+	for _, ptr := range ptrs {
+		acc += hashMsgTx(ptr)
+	}
+	return acc
+}
+
+// BenchmarkMsgTx_TxHash benchmarks computing the txid of every transaction
+// of each MsgTx layout.
+func BenchmarkMsgTx_TxHash(b *testing.B) {
+	s := pvSuite[msgtxParams, wire.MsgTx]{
+		builders:    msgtxBuilders,
+		read:        hashMsgTx,
+		sumValues:   sumMsgTxHashValues,
+		sumPointers: sumMsgTxHashPointers,
+		layouts:     msgtxLayouts(hashMsgTx),
+	}
+	s.sliceIterate(b, msgtxWireDatasets())
+}
+
+// hashMsgTxWitness computes the wtxid of tx.
+func hashMsgTxWitness(tx *wire.MsgTx) int64 {
+	h := tx.WitnessHash()
+	return int64(h[0])
+}
+
+// sumMsgTxWitnessHashValues is hashMsgTxWitness over a []wire.MsgTx.
+func sumMsgTxWitnessHashValues(vals []wire.MsgTx) int64 {
+	var acc int64
+	for _, val := range vals {
+		acc += hashMsgTxWitness(&val)
+	}
+	return acc
+}
+
+// sumMsgTxWitnessHashPointers is hashMsgTxWitness over a []*wire.MsgTx.
+func sumMsgTxWitnessHashPointers(ptrs []*wire.MsgTx) int64 {
+	var acc int64
+	for _, ptr := range ptrs {
+		acc += hashMsgTxWitness(ptr)
+	}
+	return acc
+}
+
+// BenchmarkMsgTx_WitnessHash benchmarks computing the wtxid of every
+// transaction of each MsgTx layout, which serializes the witness too.
+func BenchmarkMsgTx_WitnessHash(b *testing.B) {
+	s := pvSuite[msgtxParams, wire.MsgTx]{
+		builders:    msgtxBuilders,
+		read:        hashMsgTxWitness,
+		sumValues:   sumMsgTxWitnessHashValues,
+		sumPointers: sumMsgTxWitnessHashPointers,
+		layouts:     msgtxLayouts(hashMsgTxWitness),
+	}
+	s.sliceIterate(b, msgtxWireDatasets())
+}
@@ -0,0 +1,122 @@
+package pv
+
+import (
+	"fmt"
+	"runtime"
+	"sync"
+	"testing"
+	"unsafe"
+)
+
+// paddedUtxo rounds a Utxo up to a multiple of 128 bytes, two cache lines, so
+// no two elements of a []paddedUtxo share a line or an adjacent-line
+// prefetch pair. It is the no-false-sharing control for the mutate
+// benchmarks.
+type paddedUtxo struct {
+	Utxo
+	_ [128 - unsafe.Sizeof(Utxo{})%128]byte
+}
+
+func buildPaddedUtxos(n int, pkScript []byte) []paddedUtxo {
+	s := make([]paddedUtxo, n)
+	for i := range s {
+		s[i].Utxo = makeUtxoValue(i, pkScript)
+	}
+	return s
+}
+
+// partition assigns each writer a disjoint set of indices.
+type partition struct {
+	name string
+	span func(w, writers, n int) (start, end, step int)
+}
+
+var mutatePartitions = []partition{
+	{
+		// Chunked gives each writer one contiguous range; lines are shared
+		// only at range boundaries.
+		name: "Chunked",
+		span: func(w, writers, n int) (int, int, int) {
+			return w * n / writers, (w + 1) * n / writers, 1
+		},
+	},
+	{
+		// Strided interleaves writers element by element, so neighbouring
+		// elements always belong to different writers.
+		name: "Strided",
+		span: func(w, writers, n int) (int, int, int) {
+			return w, n, writers
+		},
+	},
+}
+
+// runMutate starts writers goroutines with GOMAXPROCS set to writers. Each
+// makes b.N passes over its own indices, calling touch on every one, so one
+// op is one pass by every writer at once.
+func runMutate(b *testing.B, n, writers int, part partition, touch func(i int)) {
+	defer runtime.GOMAXPROCS(runtime.GOMAXPROCS(writers))
+	b.ReportAllocs()
+	var start, done sync.WaitGroup
+	start.Add(1)
+	done.Add(writers)
+	for w := 0; w < writers; w++ {
+		lo, hi, step := part.span(w, writers, n)
+		go func() {
+			defer done.Done()
+			start.Wait()
+			for range b.N {
+				for i := lo; i < hi; i += step {
+					touch(i)
+				}
+			}
+		}()
+	}
+	b.ResetTimer()
+	start.Done()
+	done.Wait()
+}
+
+// BenchmarkUtxo_ConcurrentMutate benchmarks concurrent writers updating
+// Confirmations and Locked on disjoint elements of a shared Utxo set. In a
+// []Utxo adjacent elements share cache lines, so writers on neighbouring
+// elements invalidate each other's lines; []*Utxo elements live wherever the
+// allocator put them; []paddedUtxo gives every element its own lines.
+// Example prefix: "04096-Utxos-34-Script-Strided-4-Writers".
+func BenchmarkUtxo_ConcurrentMutate(b *testing.B) {
+	datasets := generateDatasets(benchConfig[utxoParams]{
+		dims:       utxoDims(func(i int) int { return 1024 << (2 * i) }, constantGrowth(34)),
+		iterations: 3,
+	})
+	for _, d := range datasets {
+		pkScript := makePkScript(d.p.scriptSize)
+		vals := buildUtxoValues(d.p.numUtxos, pkScript)
+		ptrs := buildUtxoPointers(d.p.numUtxos, pkScript)
+		padded := buildPaddedUtxos(d.p.numUtxos, pkScript)
+		for _, part := range mutatePartitions {
+			for _, writers := range parallelProcs {
+				prefix := d.name() + fmt.Sprintf("-%s-%d-Writers", part.name, writers)
+				b.Run(prefix+"/0-Values", func(b *testing.B) {
+					runMutate(b, d.p.numUtxos, writers, part, func(i int) {
+						u := &vals[i]
+						u.Confirmations++
+						u.Locked = !u.Locked
+					})
+				})
+				b.Run(prefix+"/1-Pointers", func(b *testing.B) {
+					runMutate(b, d.p.numUtxos, writers, part, func(i int) {
+						u := ptrs[i]
+						u.Confirmations++
+						u.Locked = !u.Locked
+					})
+				})
+				b.Run(prefix+"/2-Padded", func(b *testing.B) {
+					runMutate(b, d.p.numUtxos, writers, part, func(i int) {
+						u := &padded[i]
+						u.Confirmations++
+						u.Locked = !u.Locked
+					})
+				})
+			}
+		}
+	}
+}
@@ -1,7 +1,6 @@
 package pv
 
 import (
-	"fmt"
 	"testing"
 
 	"github.com/btcsuite/btcd/chaincfg/chainhash"
@@ -32,100 +31,78 @@ func buildOutPointPointers(n int) []*wire.OutPoint {
 	return s
 }
 
+type outpointParams struct {
+	numOutPoints int
+}
+
+// outpointDims names the OutPoint count dimension.
+// Example prefix: "04096-OutPoints".
+func outpointDims(outpointGrowth growthFunc) []dimension[outpointParams] {
+	return []dimension[outpointParams]{
+		{label: "OutPoints", growth: outpointGrowth, set: func(p *outpointParams, v int) { p.numOutPoints = v }},
+	}
+}
+
+// readOutPoint is the accessor summed by the OutPoint iterate benchmarks.
+func readOutPoint(op *wire.OutPoint) int64 {
+	return int64(op.Index) + int64(op.Hash[0])
+}
+
+// sumOutPointValues is readOutPoint over a []wire.OutPoint.
+func sumOutPointValues(vals []wire.OutPoint) int64 {
+	var acc int64
+	for _, val := range vals {
+		acc += readOutPoint(&val)
+	}
+	return acc
+}
+
+// sumOutPointPointers is readOutPoint over a []*wire.OutPoint.
+func sumOutPointPointers(ptrs []*wire.OutPoint) int64 {
+	var acc int64
+	for _, ptr := range ptrs {
+		acc += readOutPoint(ptr)
+	}
+	return acc
+}
+
+var outpointSuite = pvSuite[outpointParams, wire.OutPoint]{
+	builders: func(p outpointParams) (func() []wire.OutPoint, func() []*wire.OutPoint) {
+		return func() []wire.OutPoint { return buildOutPointValues(p.numOutPoints) },
+			func() []*wire.OutPoint { return buildOutPointPointers(p.numOutPoints) }
+	},
+	read:        readOutPoint,
+	sumValues:   sumOutPointValues,
+	sumPointers: sumOutPointPointers,
+}
+
+// outpointDatasets is the standard OutPoint count progression.
+func outpointDatasets() []dataset[outpointParams] {
+	return generateDatasets(benchConfig[outpointParams]{
+		dims:       outpointDims(scaleGrowth(8, exponentialGrowth())),
+		iterations: 8,
+	})
+}
+
 // BenchmarkOutPoint_SliceBuild benchmarks building slices of OutPoint values vs pointers
 func BenchmarkOutPoint_SliceBuild(b *testing.B) {
-	datasets := generateOutPointDatasets(outpointBenchConfig{
-		outpointGrowth: scaleGrowth(8, exponentialGrowth()),
-		iterations:     8,
-	})
-	for _, d := range datasets {
-		prefix := d.name()
-		b.Run(prefix+"/0-Values", func(b *testing.B) {
-			b.ReportAllocs()
-			var s []wire.OutPoint
-			for b.Loop() {
-				s = buildOutPointValues(d.numOutPoints)
-			}
-			sinkInt = len(s)
-		})
-		b.Run(prefix+"/1-Pointers", func(b *testing.B) {
-			b.ReportAllocs()
-			var s []*wire.OutPoint
-			for b.Loop() {
-				s = buildOutPointPointers(d.numOutPoints)
-			}
-			sinkInt = len(s)
-		})
-	}
+	outpointSuite.sliceBuild(b, outpointDatasets())
 }
 
 // BenchmarkOutPoint_SliceIterate benchmarks iterating over slices of OutPoint values vs pointers
 func BenchmarkOutPoint_SliceIterate(b *testing.B) {
-	datasets := generateOutPointDatasets(outpointBenchConfig{
-		outpointGrowth: scaleGrowth(8, exponentialGrowth()),
-		iterations:     8,
-	})
-	for _, d := range datasets {
-		vals := buildOutPointValues(d.numOutPoints)
-		ptrs := buildOutPointPointers(d.numOutPoints)
-		prefix := d.name()
-		b.Run(prefix+"/0-Values", func(b *testing.B) {
-			b.ReportAllocs()
-			var acc int64
-			for b.Loop() {
-				for _, val := range vals {
-					acc += int64(val.Index) + int64(val.Hash[0])
-				}
-			}
-			sinkI64 = acc
-		})
-		b.Run(prefix+"/1-Pointers", func(b *testing.B) {
-			b.ReportAllocs()
-			var acc int64
-			for b.Loop() {
-				for _, ptr := range ptrs {
-					acc += int64(ptr.Index) + int64(ptr.Hash[0])
-				}
-			}
-			sinkI64 = acc
-		})
-	}
+	outpointSuite.sliceIterate(b, outpointDatasets())
+}
+
+// BenchmarkOutPoint_SeqIterate benchmarks iter.Seq and iter.Seq2 iterators over
+// slices of OutPoint values vs pointers next to the plain range loops.
+func BenchmarkOutPoint_SeqIterate(b *testing.B) {
+	outpointSuite.seqIterate(b, outpointDatasets())
 }
 
 // BenchmarkOutPoint_SliceBuildAndIterate benchmarks building and iterating over slices
 // of OutPoint values vs pointers with repeated reads.
 func BenchmarkOutPoint_SliceBuildAndIterate(b *testing.B) {
-	d := outpointDataset{
-		numOutPoints: 128,
-		maxOutPoints: 128,
-	}
-	for i := range 10 {
-		prefix := d.name() + fmt.Sprintf("NReads%d", i)
-		b.Run(prefix+"/0-Values", func(b *testing.B) {
-			b.ReportAllocs()
-			var acc int64
-			for b.Loop() {
-				vals := buildOutPointValues(d.numOutPoints)
-				for range i {
-					for _, val := range vals {
-						acc += int64(val.Index) + int64(val.Hash[0])
-					}
-				}
-			}
-			sinkI64 = acc
-		})
-		b.Run(prefix+"/1-Pointers", func(b *testing.B) {
-			b.ReportAllocs()
-			var acc int64
-			for b.Loop() {
-				ptrs := buildOutPointPointers(d.numOutPoints)
-				for range i {
-					for _, ptr := range ptrs {
-						acc += int64(ptr.Index) + int64(ptr.Hash[0])
-					}
-				}
-			}
-			sinkI64 = acc
-		})
-	}
+	d := singleDataset(outpointDims(constantGrowth(128)))
+	outpointSuite.sliceBuildAndIterate(b, d, 10)
 }
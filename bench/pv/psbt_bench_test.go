@@ -0,0 +1,270 @@
+package pv
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/btcsuite/btcd/btcec/v2/ecdsa"
+	"github.com/btcsuite/btcd/btcutil/psbt"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcd/txscript"
+	"github.com/btcsuite/btcd/wire"
+)
+
+// psbtFingerprint is the master key fingerprint of every BIP 32 derivation
+// in the fixtures.
+const psbtFingerprint = 0x5c3f1a2b
+
+// psbtFixture is the key material behind a generated PSBT: one P2WPKH
+// previous output, key and signature per input, and one P2WPKH script per
+// output. Signing is slow, so it happens once per dataset.
+type psbtFixture struct {
+	prevScripts [][]byte
+	pubKeys     [][]byte
+	sigs        [][]byte
+	outScripts  [][]byte
+}
+
+func newPsbtFixture(nIn, nOut int) psbtFixture {
+	f := psbtFixture{
+		prevScripts: make([][]byte, nIn),
+		pubKeys:     make([][]byte, nIn),
+		sigs:        make([][]byte, nIn),
+		outScripts:  make([][]byte, nOut),
+	}
+	for i := 0; i < nIn; i++ {
+		priv, pub := scriptKey(i)
+		f.prevScripts[i] = makeScript(scriptP2WPKH, i)
+		f.pubKeys[i] = pub.SerializeCompressed()
+		digest := chainhash.DoubleHashB(f.prevScripts[i])
+		sig := ecdsa.Sign(priv, digest).Serialize()
+		f.sigs[i] = append(sig, byte(txscript.SigHashAll))
+	}
+	for k := 0; k < nOut; k++ {
+		f.outScripts[k] = makeScript(scriptP2WPKH, nIn+k)
+	}
+	return f
+}
+
+// bip32Path returns the BIP 84 path of address index i on branch.
+func bip32Path(branch, i uint32) []uint32 {
+	const hardened = 0x80000000
+	return []uint32{84 + hardened, hardened, hardened, branch, i}
+}
+
+// makePInputValue returns input i as signed by one party: its witness UTXO,
+// a partial signature and the key's derivation.
+func makePInputValue(f *psbtFixture, i int) psbt.PInput {
+	return psbt.PInput{
+		WitnessUtxo: &wire.TxOut{Value: int64(10000 + i), PkScript: f.prevScripts[i]},
+		PartialSigs: []*psbt.PartialSig{{PubKey: f.pubKeys[i], Signature: f.sigs[i]}},
+		SighashType: txscript.SigHashAll,
+		Bip32Derivation: []*psbt.Bip32Derivation{{
+			PubKey:               f.pubKeys[i],
+			MasterKeyFingerprint: psbtFingerprint,
+			Bip32Path:            bip32Path(0, uint32(i)),
+		}},
+	}
+}
+
+func makePInputPointer(f *psbtFixture, i int) *psbt.PInput {
+	return &psbt.PInput{
+		WitnessUtxo: &wire.TxOut{Value: int64(10000 + i), PkScript: f.prevScripts[i]},
+		PartialSigs: []*psbt.PartialSig{{PubKey: f.pubKeys[i], Signature: f.sigs[i]}},
+		SighashType: txscript.SigHashAll,
+		Bip32Derivation: []*psbt.Bip32Derivation{{
+			PubKey:               f.pubKeys[i],
+			MasterKeyFingerprint: psbtFingerprint,
+			Bip32Path:            bip32Path(0, uint32(i)),
+		}},
+	}
+}
+
+func buildPInputValues(f *psbtFixture) []psbt.PInput {
+	s := make([]psbt.PInput, len(f.pubKeys))
+	for i := range s {
+		s[i] = makePInputValue(f, i)
+	}
+	return s
+}
+
+func buildPInputPointers(f *psbtFixture) []*psbt.PInput {
+	s := make([]*psbt.PInput, len(f.pubKeys))
+	for i := range s {
+		s[i] = makePInputPointer(f, i)
+	}
+	return s
+}
+
+// buildPsbtPacket creates a packet with psbt.New and fills in every input,
+// and the derivation of the change output, the last one.
+func buildPsbtPacket(f *psbtFixture) *psbt.Packet {
+	nIn, nOut := len(f.pubKeys), len(f.outScripts)
+	inputs := make([]*wire.OutPoint, nIn)
+	sequences := make([]uint32, nIn)
+	for i := range inputs {
+		inputs[i] = &wire.OutPoint{Hash: chainhash.Hash{byte(i), byte(i >> 8)}, Index: uint32(i % 4)}
+		sequences[i] = wire.MaxTxInSequenceNum - 2
+	}
+	outputs := make([]*wire.TxOut, nOut)
+	for k := range outputs {
+		outputs[k] = &wire.TxOut{Value: int64(5000 + k), PkScript: f.outScripts[k]}
+	}
+	p, err := psbt.New(inputs, outputs, 2, 0, sequences)
+	if err != nil {
+		panic(err)
+	}
+	for i := range p.Inputs {
+		p.Inputs[i] = makePInputValue(f, i)
+	}
+	p.Outputs[nOut-1].Bip32Derivation = []*psbt.Bip32Derivation{{
+		PubKey:               f.pubKeys[0],
+		MasterKeyFingerprint: psbtFingerprint,
+		Bip32Path:            bip32Path(1, 0),
+	}}
+	return p
+}
+
+// readPInput is the accessor summed by the PInput iterate benchmarks: the
+// value being spent and the number of signatures collected.
+func readPInput(in *psbt.PInput) int64 {
+	return in.WitnessUtxo.Value + int64(len(in.PartialSigs))
+}
+
+// sumPInputValues is readPInput over a []psbt.PInput.
+func sumPInputValues(vals []psbt.PInput) int64 {
+	var acc int64
+	for _, val := range vals {
+		acc += readPInput(&val)
+	}
+	return acc
+}
+
+// sumPInputPointers is readPInput over a []*psbt.PInput.
+func sumPInputPointers(ptrs []*psbt.PInput) int64 {
+	var acc int64
+	for _, ptr := range ptrs {
+		acc += readPInput(ptr)
+	}
+	return acc
+}
+
+type psbtParams struct {
+	numInputs  int
+	numOutputs int
+}
+
+// psbtDims names the input and output count dimensions.
+// Example prefix: "512-Ins-256-Outs".
+func psbtDims(inputGrowth, outputGrowth growthFunc) []dimension[psbtParams] {
+	return []dimension[psbtParams]{
+		{label: "Ins", growth: inputGrowth, set: func(p *psbtParams, v int) { p.numInputs = v }},
+		{label: "Outs", growth: outputGrowth, set: func(p *psbtParams, v int) { p.numOutputs = v }},
+	}
+}
+
+// psbtDatasets is the lock-step PSBT progression, 4x2 to 512x256.
+func psbtDatasets() []dataset[psbtParams] {
+	return generateDatasets(benchConfig[psbtParams]{
+		dims:       psbtDims(scaleGrowth(4, exponentialGrowth()), scaleGrowth(2, exponentialGrowth())),
+		iterations: 8,
+	})
+}
+
+// pinputSuite compares upstream's Packet.Inputs layout, a []psbt.PInput,
+// with a []*psbt.PInput.
+var pinputSuite = pvSuite[psbtParams, psbt.PInput]{
+	builders: func(p psbtParams) (func() []psbt.PInput, func() []*psbt.PInput) {
+		f := newPsbtFixture(p.numInputs, p.numOutputs)
+		return func() []psbt.PInput { return buildPInputValues(&f) },
+			func() []*psbt.PInput { return buildPInputPointers(&f) }
+	},
+	read:        readPInput,
+	sumValues:   sumPInputValues,
+	sumPointers: sumPInputPointers,
+}
+
+// BenchmarkPInput_SliceBuild benchmarks building slices of psbt.PInput values vs pointers
+func BenchmarkPInput_SliceBuild(b *testing.B) {
+	pinputSuite.sliceBuild(b, psbtDatasets())
+}
+
+// BenchmarkPInput_SliceIterate benchmarks summing WitnessUtxo values over
+// slices of psbt.PInput values vs pointers.
+func BenchmarkPInput_SliceIterate(b *testing.B) {
+	pinputSuite.sliceIterate(b, psbtDatasets())
+}
+
+// BenchmarkPsbt_Build benchmarks creating a whole packet: the unsigned
+// transaction, every input's fields and the output derivations.
+func BenchmarkPsbt_Build(b *testing.B) {
+	for _, d := range psbtDatasets() {
+		f := newPsbtFixture(d.p.numInputs, d.p.numOutputs)
+		b.Run(d.name()+"/0-Packet", func(b *testing.B) {
+			b.ReportAllocs()
+			var p *psbt.Packet
+			m := startGCMeter()
+			for b.Loop() {
+				p = buildPsbtPacket(&f)
+			}
+			m.report(b)
+			p = nil
+			b.ReportMetric(retainedObjects(func() { p = buildPsbtPacket(&f) }), "heap-objs")
+			sinkInt = len(p.Inputs)
+		})
+	}
+}
+
+// BenchmarkPsbt_B64 benchmarks the base64 form a signing service exchanges:
+// encoding a packet, decoding it, and both in turn.
+func BenchmarkPsbt_B64(b *testing.B) {
+	for _, d := range psbtDatasets() {
+		f := newPsbtFixture(d.p.numInputs, d.p.numOutputs)
+		packet := buildPsbtPacket(&f)
+		encoded, err := packet.B64Encode()
+		if err != nil {
+			b.Fatal(err)
+		}
+		prefix := d.name()
+		b.Run(prefix+"/0-Encode", func(b *testing.B) {
+			b.ReportAllocs()
+			m := startGCMeter()
+			for b.Loop() {
+				s, err := packet.B64Encode()
+				if err != nil {
+					b.Fatal(err)
+				}
+				sinkInt = len(s)
+			}
+			m.report(b)
+		})
+		b.Run(prefix+"/1-Decode", func(b *testing.B) {
+			b.ReportAllocs()
+			m := startGCMeter()
+			for b.Loop() {
+				p, err := psbt.NewFromRawBytes(strings.NewReader(encoded), true)
+				if err != nil {
+					b.Fatal(err)
+				}
+				sinkInt = len(p.Inputs)
+			}
+			m.report(b)
+		})
+		b.Run(prefix+"/2-RoundTrip", func(b *testing.B) {
+			b.ReportAllocs()
+			m := startGCMeter()
+			for b.Loop() {
+				p, err := psbt.NewFromRawBytes(strings.NewReader(encoded), true)
+				if err != nil {
+					b.Fatal(err)
+				}
+				s, err := p.B64Encode()
+				if err != nil {
+					b.Fatal(err)
+				}
+				sinkInt = len(s)
+			}
+			m.report(b)
+		})
+	}
+}
@@ -0,0 +1,182 @@
+package pv
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcd/wire"
+)
+
+// typedPool is a sync.Pool of *T.
+type typedPool[T any] struct {
+	p sync.Pool
+}
+
+func newTypedPool[T any]() *typedPool[T] {
+	return &typedPool[T]{p: sync.Pool{New: func() any { return new(T) }}}
+}
+
+func (tp *typedPool[T]) get() *T  { return tp.p.Get().(*T) }
+func (tp *typedPool[T]) put(v *T) { tp.p.Put(v) }
+
+// resizeBytes returns b resized to n bytes, reusing its backing array when it
+// is large enough.
+func resizeBytes(b []byte, n int) []byte {
+	if n < 0 {
+		n = 0
+	}
+	if cap(b) >= n {
+		return b[:n]
+	}
+	return make([]byte, n)
+}
+
+// runReuse registers the reuse-across-iterations subjects for n elements of T
+// of dataset p of suite. Every iteration builds the set, reads it once with
+// the suite's sums and drops it, the way an RPC handler serves a
+// ListUnspent-style request:
+//   - 0-Values: a fresh []T from the suite's value builder.
+//   - 1-Pointers: a fresh []*T from the suite's pointer builder.
+//   - 2-Values-Reuse: one []T kept across iterations and refilled in place.
+//   - 3-Pointers-Pool: a []*T whose elements come from and go back to a
+//     sync.Pool.
+//
+// fill overwrites v in place and should reuse any buffers v already holds.
+// Each subject also reports gcs/op and gc-pause-ns/op so steady-state GC
+// load can be compared.
+func runReuse[P, T any](b *testing.B, prefix string, suite pvSuite[P, T], p P, n int, fill func(v *T, i int)) {
+	values, pointers := suite.builders(p)
+	b.Run(prefix+"/0-Values", func(b *testing.B) {
+		b.ReportAllocs()
+		var acc int64
+		m := startGCMeter()
+		for b.Loop() {
+			acc += suite.sumValues(values())
+		}
+		m.report(b)
+		sinkI64 = acc
+	})
+	b.Run(prefix+"/1-Pointers", func(b *testing.B) {
+		b.ReportAllocs()
+		var acc int64
+		m := startGCMeter()
+		for b.Loop() {
+			acc += suite.sumPointers(pointers())
+		}
+		m.report(b)
+		sinkI64 = acc
+	})
+	b.Run(prefix+"/2-Values-Reuse", func(b *testing.B) {
+		b.ReportAllocs()
+		var acc int64
+		var s []T
+		m := startGCMeter()
+		for b.Loop() {
+			if cap(s) < n {
+				s = make([]T, 0, n)
+			}
+			s = s[:n]
+			for i := range s {
+				fill(&s[i], i)
+			}
+			acc += suite.sumValues(s)
+		}
+		m.report(b)
+		sinkI64 = acc
+	})
+	b.Run(prefix+"/3-Pointers-Pool", func(b *testing.B) {
+		b.ReportAllocs()
+		pool := newTypedPool[T]()
+		var acc int64
+		var s []*T
+		m := startGCMeter()
+		for b.Loop() {
+			s = s[:0]
+			for i := 0; i < n; i++ {
+				v := pool.get()
+				fill(v, i)
+				s = append(s, v)
+			}
+			acc += suite.sumPointers(s)
+			for _, v := range s {
+				pool.put(v)
+			}
+		}
+		m.report(b)
+		sinkI64 = acc
+	})
+}
+
+// fillTxIn overwrites ti in place, reusing its SignatureScript buffer.
+func fillTxIn(ti *wire.TxIn, i, scriptSize int) {
+	ti.PreviousOutPoint = wire.OutPoint{Hash: chainhash.Hash{byte(i % 251)}, Index: uint32(i)}
+	ti.SignatureScript = resizeBytes(ti.SignatureScript, scriptSize)
+	ti.Witness = nil
+	ti.Sequence = uint32(100000 + i)
+}
+
+// fillMsgTx overwrites tx in place, reusing its inputs, outputs and their
+// scripts when tx already has them.
+func fillMsgTx(tx *wire.MsgTx, i, nIn, nOut, scriptSize int) {
+	tx.Version = 2
+	tx.LockTime = 0
+	if cap(tx.TxIn) < nIn {
+		tx.TxIn = make([]*wire.TxIn, nIn)
+	}
+	tx.TxIn = tx.TxIn[:nIn]
+	for j := 0; j < nIn; j++ {
+		if tx.TxIn[j] == nil {
+			tx.TxIn[j] = &wire.TxIn{}
+		}
+		ti := tx.TxIn[j]
+		ti.PreviousOutPoint = wire.OutPoint{Hash: chainhash.Hash{byte((i + j) % 251)}, Index: uint32(i + j)}
+		ti.SignatureScript = resizeBytes(ti.SignatureScript, scriptSize)
+		ti.Witness = nil
+		ti.Sequence = uint32(100000 + i + j)
+	}
+	if cap(tx.TxOut) < nOut {
+		tx.TxOut = make([]*wire.TxOut, nOut)
+	}
+	tx.TxOut = tx.TxOut[:nOut]
+	for k := 0; k < nOut; k++ {
+		if tx.TxOut[k] == nil {
+			tx.TxOut[k] = &wire.TxOut{}
+		}
+		to := tx.TxOut[k]
+		to.Value = int64(1000 + i + k)
+		to.PkScript = resizeBytes(to.PkScript, scriptSize)
+	}
+}
+
+// BenchmarkUtxo_Reuse benchmarks repeated build-read-release cycles of Utxo
+// sets: fresh values, fresh pointers, a reused value backing array and
+// sync.Pool-recycled pointers.
+func BenchmarkUtxo_Reuse(b *testing.B) {
+	for _, d := range utxoDatasets() {
+		pkScript := makePkScript(d.p.scriptSize)
+		fill := func(u *Utxo, i int) { *u = makeUtxoValue(i, pkScript) }
+		runReuse(b, d.name(), utxoSuite, d.p, d.p.numUtxos, fill)
+	}
+}
+
+// BenchmarkTxIn_Reuse benchmarks repeated build-read-release cycles of TxIn
+// sets across the same four strategies as BenchmarkUtxo_Reuse.
+func BenchmarkTxIn_Reuse(b *testing.B) {
+	for _, d := range txinDatasets() {
+		fill := func(ti *wire.TxIn, i int) { fillTxIn(ti, i, d.p.scriptSize) }
+		runReuse(b, d.name(), txinSuite, d.p, d.p.numTxIns, fill)
+	}
+}
+
+// BenchmarkMsgTx_Reuse benchmarks repeated build-read-release cycles of MsgTx
+// sets. Reused and pooled transactions keep their inputs, outputs and script
+// buffers between iterations.
+func BenchmarkMsgTx_Reuse(b *testing.B) {
+	for _, d := range msgtxDatasets() {
+		fill := func(tx *wire.MsgTx, i int) {
+			fillMsgTx(tx, i, d.p.nInputs, d.p.nOutputs, d.p.scriptSize)
+		}
+		runReuse(b, d.name(), msgtxSuite, d.p, d.p.numTxs, fill)
+	}
+}
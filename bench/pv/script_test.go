@@ -0,0 +1,262 @@
+package pv
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/btcsuite/btcd/chaincfg"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcd/txscript"
+)
+
+// scriptType is a standard output script the fixtures can generate.
+type scriptType int
+
+const (
+	scriptP2PKH scriptType = iota
+	scriptP2WPKH
+	scriptP2SH
+	scriptP2WSH
+	scriptP2TR
+)
+
+var scriptTypeNames = [...]string{
+	scriptP2PKH:  "P2PKH",
+	scriptP2WPKH: "P2WPKH",
+	scriptP2SH:   "P2SH",
+	scriptP2WSH:  "P2WSH",
+	scriptP2TR:   "P2TR",
+}
+
+func (t scriptType) String() string { return scriptTypeNames[t] }
+
+// scriptKey returns the deterministic key pair for key index i.
+func scriptKey(i int) (*btcec.PrivateKey, *btcec.PublicKey) {
+	var seed [8]byte
+	binary.BigEndian.PutUint64(seed[:], uint64(i))
+	return btcec.PrivKeyFromBytes(chainhash.HashB(seed[:]))
+}
+
+// multisigScript returns the 2-of-3 redeem script over keys i, i+1 and i+2
+// that the P2SH and P2WSH fixtures commit to.
+func multisigScript(i int) []byte {
+	keys := make([]*btcutil.AddressPubKey, 3)
+	for j := range keys {
+		_, pub := scriptKey(i + j)
+		k, err := btcutil.NewAddressPubKey(pub.SerializeCompressed(), &chaincfg.MainNetParams)
+		if err != nil {
+			panic(err)
+		}
+		keys[j] = k
+	}
+	script, err := txscript.MultiSigScript(keys, 2)
+	if err != nil {
+		panic(err)
+	}
+	return script
+}
+
+// makeScript returns the output script of type t for key index i. Scripts
+// for the same t and i are always identical.
+func makeScript(t scriptType, i int) []byte {
+	params := &chaincfg.MainNetParams
+	_, pub := scriptKey(i)
+	var (
+		addr btcutil.Address
+		err  error
+	)
+	switch t {
+	case scriptP2PKH:
+		addr, err = btcutil.NewAddressPubKeyHash(btcutil.Hash160(pub.SerializeCompressed()), params)
+	case scriptP2WPKH:
+		addr, err = btcutil.NewAddressWitnessPubKeyHash(btcutil.Hash160(pub.SerializeCompressed()), params)
+	case scriptP2SH:
+		addr, err = btcutil.NewAddressScriptHash(multisigScript(i), params)
+	case scriptP2WSH:
+		h := sha256.Sum256(multisigScript(i))
+		addr, err = btcutil.NewAddressWitnessScriptHash(h[:], params)
+	case scriptP2TR:
+		script, err := txscript.PayToTaprootScript(txscript.ComputeTaprootKeyNoScript(pub))
+		if err != nil {
+			panic(err)
+		}
+		return script
+	}
+	if err != nil {
+		panic(err)
+	}
+	script, err := txscript.PayToAddrScript(addr)
+	if err != nil {
+		panic(err)
+	}
+	return script
+}
+
+// scriptWeight is one script type's share of a scriptMix.
+type scriptWeight struct {
+	typ    scriptType
+	weight int
+}
+
+// scriptMix is a weighted distribution of script types. Its name is the
+// b.Run prefix segment of datasets that use it.
+type scriptMix struct {
+	name    string
+	weights []scriptWeight
+}
+
+// singleScript is the mix made only of t.
+func singleScript(t scriptType) scriptMix {
+	return scriptMix{name: t.String(), weights: []scriptWeight{{typ: t, weight: 1}}}
+}
+
+// scriptMixes are the mixes a script dimension steps through: every type on
+// its own, then a wallet-like blend dominated by native segwit.
+var scriptMixes = []scriptMix{
+	singleScript(scriptP2PKH),
+	singleScript(scriptP2WPKH),
+	singleScript(scriptP2SH),
+	singleScript(scriptP2WSH),
+	singleScript(scriptP2TR),
+	{
+		name: "Mixed",
+		weights: []scriptWeight{
+			{typ: scriptP2WPKH, weight: 60},
+			{typ: scriptP2TR, weight: 20},
+			{typ: scriptP2PKH, weight: 10},
+			{typ: scriptP2SH, weight: 5},
+			{typ: scriptP2WSH, weight: 5},
+		},
+	},
+}
+
+// pick returns the script type of element i. Elements are scattered by a
+// multiplicative hash rather than grouped into runs, so iteration sees the
+// types interleaved the way a real wallet's outputs are.
+func (m scriptMix) pick(i int) scriptType {
+	total := 0
+	for _, w := range m.weights {
+		total += w.weight
+	}
+	r := scatter(i, total)
+	for _, w := range m.weights {
+		if r < w.weight {
+			return w.typ
+		}
+		r -= w.weight
+	}
+	return m.weights[len(m.weights)-1].typ
+}
+
+// scatter maps element i to [0, n) with a multiplicative hash, so weighted
+// picks over consecutive elements interleave instead of forming runs.
+func scatter(i, n int) int {
+	return int(uint32(i)*2654435761) % n
+}
+
+// scripts returns n output scripts drawn from the mix, one key per element.
+func (m scriptMix) scripts(n int) [][]byte {
+	s := make([][]byte, n)
+	for i := range s {
+		s[i] = makeScript(m.pick(i), i)
+	}
+	return s
+}
+
+// scriptDim is the categorical script mix dimension. Its growth index selects
+// an entry of scriptMixes, named in the prefix by the mix name, e.g.
+// "04096-Utxos-P2TR".
+func scriptDim[P any](set func(p *P, m scriptMix)) dimension[P] {
+	return dimension[P]{
+		label:  "ScriptType",
+		growth: func(i int) int { return i },
+		set:    func(p *P, v int) { set(p, scriptMixes[v]) },
+		steps:  len(scriptMixes),
+		format: func(v, _ int) string { return "-" + scriptMixes[v].name },
+	}
+}
+
+// scriptAlloc is how a builder allocates the scripts of the elements it
+// builds.
+type scriptAlloc int
+
+const (
+	// allocDefault keeps the suite's historical strategy, so datasets
+	// without an allocation dimension measure what they always have:
+	// shared for Utxo, per-element for the wire types.
+	allocDefault scriptAlloc = iota
+
+	// allocShared points every element at one script made outside the
+	// timed build.
+	allocShared
+
+	// allocPerElement makes a fresh script for every element.
+	allocPerElement
+
+	// allocArena makes one block per build and slices it per element.
+	allocArena
+)
+
+var scriptAllocNames = [...]string{
+	allocShared:     "Shared",
+	allocPerElement: "PerElement",
+	allocArena:      "Arena",
+}
+
+// or returns a, or def when a is allocDefault.
+func (a scriptAlloc) or(def scriptAlloc) scriptAlloc {
+	if a == allocDefault {
+		return def
+	}
+	return a
+}
+
+// scripts starts handing out the scripts of one build. shared backs every
+// script under allocShared and must be at least as long as the longest one.
+// total is the sum of all script sizes; allocArena allocates it here, so
+// builders call scripts inside the timed build.
+func (a scriptAlloc) scripts(shared []byte, total int) scriptAllocator {
+	sa := scriptAllocator{alloc: a, shared: shared}
+	if a == allocArena {
+		sa.arena = make([]byte, total)
+	}
+	return sa
+}
+
+// scriptAllocator hands out scripts under one scriptAlloc.
+type scriptAllocator struct {
+	alloc  scriptAlloc
+	shared []byte
+	arena  []byte
+}
+
+// next returns a script of size bytes. Shared scripts are a prefix of the
+// shared script; per-element and arena scripts are zero-filled, and arena
+// scripts are capped so appending to one cannot overwrite the next.
+func (s *scriptAllocator) next(size int) []byte {
+	switch s.alloc {
+	case allocShared:
+		return s.shared[:size:size]
+	case allocArena:
+		b := s.arena[:size:size]
+		s.arena = s.arena[size:]
+		return b
+	default:
+		return make([]byte, size)
+	}
+}
+
+// allocDim is the categorical script allocation dimension. Its growth index
+// selects allocShared, allocPerElement or allocArena. Example segment:
+// "-Arena".
+func allocDim[P any](set func(p *P, a scriptAlloc)) dimension[P] {
+	return dimension[P]{
+		label:  "Alloc",
+		growth: func(i int) int { return int(allocShared) + i },
+		set:    func(p *P, v int) { set(p, scriptAlloc(v)) },
+		steps:  len(scriptAllocNames) - 1,
+		format: func(v, _ int) string { return "-" + scriptAllocNames[v] },
+	}
+}
@@ -0,0 +1,252 @@
+package pv
+
+import (
+	"bytes"
+	"cmp"
+	"math/rand/v2"
+	"slices"
+	"sort"
+	"testing"
+	"unsafe"
+
+	"github.com/btcsuite/btcd/wire"
+)
+
+// sortShuffle returns a copy of s in a fixed random order. Slices of the
+// same length always get the same permutation, so the value and pointer
+// subjects of a dataset sort identical sequences.
+func sortShuffle[T any](s []T) []T {
+	s = slices.Clone(s)
+	rand.New(rand.NewPCG(1, 2)).Shuffle(len(s), func(i, j int) { s[i], s[j] = s[j], s[i] })
+	return s
+}
+
+// sortIdentity returns the indices 0 to n-1, the unsorted form of an index
+// permutation.
+func sortIdentity(n int) []int32 {
+	idx := make([]int32, n)
+	for i := range idx {
+		idx[i] = int32(i)
+	}
+	return idx
+}
+
+// sortable adapts a slice and comparison to sort.Interface.
+type sortable[T any] struct {
+	s   []T
+	cmp func(a, b T) int
+}
+
+func (x *sortable[T]) Len() int           { return len(x.s) }
+func (x *sortable[T]) Less(i, j int) bool { return x.cmp(x.s[i], x.s[j]) < 0 }
+func (x *sortable[T]) Swap(i, j int)      { x.s[i], x.s[j] = x.s[j], x.s[i] }
+
+// countingSortable is a sortable that counts its swaps.
+type countingSortable[T any] struct {
+	sortable[T]
+	swaps int
+}
+
+func (x *countingSortable[T]) Swap(i, j int) {
+	x.swaps++
+	x.sortable.Swap(i, j)
+}
+
+// sortBytesMoved returns the bytes one sort of unsorted writes: two elements
+// per swap. slices.SortFunc and sort.Sort are generated from the same
+// pdqsort, so a counted sort.Sort makes exactly the swaps SortFunc does.
+func sortBytesMoved[T any](unsorted []T, cmp func(a, b T) int, stable bool) float64 {
+	c := &countingSortable[T]{sortable: sortable[T]{s: slices.Clone(unsorted), cmp: cmp}}
+	if stable {
+		sort.Stable(c)
+	} else {
+		sort.Sort(c)
+	}
+	return float64(c.swaps) * 2 * float64(unsafe.Sizeof(*new(T)))
+}
+
+// runSort registers one subject of a sort benchmark. Each op restores the
+// unsorted order with a copy, then sorts with slices.SortFunc or, if stable
+// is set, sort.Stable. B-moved/op counts the sort's swaps only, not the copy.
+func runSort[T any](b *testing.B, name string, unsorted []T, cmp func(a, b T) int, stable bool) {
+	b.Run(name, func(b *testing.B) {
+		b.ReportAllocs()
+		data := &sortable[T]{s: make([]T, len(unsorted)), cmp: cmp}
+		m := startGCMeter()
+		for b.Loop() {
+			copy(data.s, unsorted)
+			if stable {
+				sort.Stable(data)
+			} else {
+				slices.SortFunc(data.s, cmp)
+			}
+		}
+		m.report(b)
+		b.ReportMetric(sortBytesMoved(unsorted, cmp, stable), "B-moved/op")
+	})
+}
+
+// runSearch registers one subject of a binary search benchmark. Each op
+// looks up every probe in sorted.
+func runSearch[T, K any](b *testing.B, name string, sorted []T, probes []K, cmp func(e T, k K) int) {
+	b.Run(name, func(b *testing.B) {
+		b.ReportAllocs()
+		m := startGCMeter()
+		for b.Loop() {
+			hits := 0
+			for _, k := range probes {
+				if _, ok := slices.BinarySearchFunc(sorted, k, cmp); ok {
+					hits++
+				}
+			}
+			sinkInt = hits
+		}
+		m.report(b)
+	})
+}
+
+// cmpOutPoint orders outpoints by hash, then index.
+func cmpOutPoint(a, b *wire.OutPoint) int {
+	if c := bytes.Compare(a.Hash[:], b.Hash[:]); c != 0 {
+		return c
+	}
+	return cmp.Compare(a.Index, b.Index)
+}
+
+// sortUtxos returns buildUtxoValues and buildUtxoPointers output in
+// sortShuffle order. The pointers keep their build-order addresses, so a
+// sorted []*Utxo reads its elements out of memory order.
+func sortUtxos(p utxoParams) ([]Utxo, []*Utxo) {
+	pkScript := makePkScript(p.scriptSize)
+	return sortShuffle(buildUtxoValues(p.numUtxos, pkScript)),
+		sortShuffle(buildUtxoPointers(p.numUtxos, pkScript))
+}
+
+// benchUtxoSort sorts shuffled Utxo datasets as a []Utxo, a []*Utxo and an
+// index permutation of the []Utxo, all by the order of ptrCmp.
+func benchUtxoSort(b *testing.B, stable bool, valCmp func(x, y Utxo) int, ptrCmp func(x, y *Utxo) int) {
+	for _, d := range utxoDatasets() {
+		vals, ptrs := sortUtxos(d.p)
+		prefix := d.name()
+		runSort(b, prefix+"/0-Values", vals, valCmp, stable)
+		runSort(b, prefix+"/1-Pointers", ptrs, ptrCmp, stable)
+		runSort(b, prefix+"/2-Index", sortIdentity(len(vals)), func(i, j int32) int {
+			return ptrCmp(&vals[i], &vals[j])
+		}, stable)
+	}
+}
+
+// BenchmarkUtxo_SortFunc benchmarks slices.SortFunc by Amount over Utxo
+// values, pointers and an index permutation.
+func BenchmarkUtxo_SortFunc(b *testing.B) {
+	benchUtxoSort(b, false,
+		func(x, y Utxo) int { return cmp.Compare(x.Amount, y.Amount) },
+		func(x, y *Utxo) int { return cmp.Compare(x.Amount, y.Amount) })
+}
+
+// BenchmarkUtxo_SortStable benchmarks sort.Stable by Confirmations, which
+// repeats every 100 elements, over Utxo values, pointers and an index
+// permutation.
+func BenchmarkUtxo_SortStable(b *testing.B) {
+	benchUtxoSort(b, true,
+		func(x, y Utxo) int { return cmp.Compare(x.Confirmations, y.Confirmations) },
+		func(x, y *Utxo) int { return cmp.Compare(x.Confirmations, y.Confirmations) })
+}
+
+// BenchmarkUtxo_BinarySearch benchmarks looking up every Utxo by OutPoint in
+// a sorted []Utxo, []*Utxo and index permutation, in shuffled order.
+func BenchmarkUtxo_BinarySearch(b *testing.B) {
+	for _, d := range utxoDatasets() {
+		vals, ptrs := sortUtxos(d.p)
+		probes := make([]wire.OutPoint, len(vals))
+		for i := range vals {
+			probes[i] = vals[i].OutPoint
+		}
+		sortedVals := slices.Clone(vals)
+		slices.SortFunc(sortedVals, func(x, y Utxo) int { return cmpOutPoint(&x.OutPoint, &y.OutPoint) })
+		sortedPtrs := slices.Clone(ptrs)
+		slices.SortFunc(sortedPtrs, func(x, y *Utxo) int { return cmpOutPoint(&x.OutPoint, &y.OutPoint) })
+		idx := sortIdentity(len(vals))
+		slices.SortFunc(idx, func(i, j int32) int { return cmpOutPoint(&vals[i].OutPoint, &vals[j].OutPoint) })
+
+		prefix := d.name()
+		runSearch(b, prefix+"/0-Values", sortedVals, probes, func(u Utxo, op wire.OutPoint) int {
+			return cmpOutPoint(&u.OutPoint, &op)
+		})
+		runSearch(b, prefix+"/1-Pointers", sortedPtrs, probes, func(u *Utxo, op wire.OutPoint) int {
+			return cmpOutPoint(&u.OutPoint, &op)
+		})
+		runSearch(b, prefix+"/2-Index", idx, probes, func(i int32, op wire.OutPoint) int {
+			return cmpOutPoint(&vals[i].OutPoint, &op)
+		})
+	}
+}
+
+// sortTxOuts returns buildTxOutValues and buildTxOutPointers output in
+// sortShuffle order.
+func sortTxOuts(p txoutParams) ([]wire.TxOut, []*wire.TxOut) {
+	pkScript := makePkScript(p.scriptSize)
+	alloc := p.alloc.or(allocPerElement)
+	return sortShuffle(buildTxOutValues(p.numTxOuts, pkScript, alloc)),
+		sortShuffle(buildTxOutPointers(p.numTxOuts, pkScript, alloc))
+}
+
+// benchTxOutSort sorts shuffled TxOut datasets by Value as a []wire.TxOut, a
+// []*wire.TxOut and an index permutation of the []wire.TxOut.
+func benchTxOutSort(b *testing.B, stable bool) {
+	for _, d := range txoutDatasets() {
+		vals, ptrs := sortTxOuts(d.p)
+		prefix := d.name()
+		runSort(b, prefix+"/0-Values", vals, func(x, y wire.TxOut) int {
+			return cmp.Compare(x.Value, y.Value)
+		}, stable)
+		runSort(b, prefix+"/1-Pointers", ptrs, func(x, y *wire.TxOut) int {
+			return cmp.Compare(x.Value, y.Value)
+		}, stable)
+		runSort(b, prefix+"/2-Index", sortIdentity(len(vals)), func(i, j int32) int {
+			return cmp.Compare(vals[i].Value, vals[j].Value)
+		}, stable)
+	}
+}
+
+// BenchmarkTxOut_SortFunc benchmarks slices.SortFunc by Value over TxOut
+// values, pointers and an index permutation.
+func BenchmarkTxOut_SortFunc(b *testing.B) {
+	benchTxOutSort(b, false)
+}
+
+// BenchmarkTxOut_SortStable benchmarks sort.Stable by Value over TxOut
+// values, pointers and an index permutation.
+func BenchmarkTxOut_SortStable(b *testing.B) {
+	benchTxOutSort(b, true)
+}
+
+// BenchmarkTxOut_BinarySearch benchmarks looking up every TxOut in a sorted
+// []wire.TxOut, []*wire.TxOut and index permutation, in shuffled order. A
+// TxOut carries no OutPoint, so the key is Value.
+func BenchmarkTxOut_BinarySearch(b *testing.B) {
+	for _, d := range txoutDatasets() {
+		vals, ptrs := sortTxOuts(d.p)
+		probes := make([]int64, len(vals))
+		for i := range vals {
+			probes[i] = vals[i].Value
+		}
+		sortedVals := slices.Clone(vals)
+		slices.SortFunc(sortedVals, func(x, y wire.TxOut) int { return cmp.Compare(x.Value, y.Value) })
+		sortedPtrs := slices.Clone(ptrs)
+		slices.SortFunc(sortedPtrs, func(x, y *wire.TxOut) int { return cmp.Compare(x.Value, y.Value) })
+		idx := sortIdentity(len(vals))
+		slices.SortFunc(idx, func(i, j int32) int { return cmp.Compare(vals[i].Value, vals[j].Value) })
+
+		prefix := d.name()
+		runSearch(b, prefix+"/0-Values", sortedVals, probes, func(to wire.TxOut, v int64) int {
+			return cmp.Compare(to.Value, v)
+		})
+		runSearch(b, prefix+"/1-Pointers", sortedPtrs, probes, func(to *wire.TxOut, v int64) int {
+			return cmp.Compare(to.Value, v)
+		})
+		runSearch(b, prefix+"/2-Index", idx, probes, func(i int32, v int64) int {
+			return cmp.Compare(vals[i].Value, v)
+		})
+	}
+}
@@ -0,0 +1,430 @@
+package pv
+
+import (
+	"fmt"
+	"iter"
+	"runtime"
+	"sync/atomic"
+	"testing"
+)
+
+// pvSuite is the standard value-vs-pointer benchmark suite for a type T over
+// datasets with parameters P. A type only supplies its builders, its slice
+// sums and a read accessor; the suite registers SliceBuild, SliceIterate and
+// SliceBuildAndIterate runs with the usual "/0-Values" and "/1-Pointers"
+// subjects. Every subject reports the GC metrics described on gcMeter.
+type pvSuite[P, T any] struct {
+	// builders returns the value and pointer slice constructors for one
+	// dataset. Anything they share, such as a pre-built pkScript, is made
+	// here, outside the timed loop.
+	builders func(p P) (values func() []T, pointers func() []*T)
+
+	// sumValues and sumPointers fold every element of a []T or []*T into
+	// the benchmark checksum for the "0-Values" and "1-Pointers" subjects.
+	// Each type writes them as plain range loops, so the element reads are
+	// inlined and the suite pays one call per pass, not one per element.
+	sumValues   func(vals []T) int64
+	sumPointers func(ptrs []*T) int64
+
+	// read folds one element into the benchmark checksum for the iterator
+	// subjects, whose yield callbacks are per element anyway.
+	read func(v *T) int64
+
+	// prefix overrides dataset.name for SliceBuild and SliceIterate runs.
+	prefix func(d dataset[P]) string
+
+	// layouts are extra representations registered after the value and
+	// pointer subjects, such as "2-SoA".
+	layouts []pvLayout[P]
+}
+
+// pvLayout is a representation of a dataset other than []T or []*T.
+type pvLayout[P any] interface {
+	runBuild(b *testing.B, prefix string, p P)
+	runIterate(b *testing.B, prefix string, p P)
+	runBuildAndIterate(b *testing.B, prefix string, p P, reads int)
+	runIterateParallel(b *testing.B, prefix string, p P, procs int)
+}
+
+// layout adapts a representation L, such as a struct of slices, to pvLayout.
+// read is called once per element through a func value, as pvSuite.read is,
+// so all subjects pay the same call overhead.
+type layout[P, L any] struct {
+	subject string
+
+	// builder returns the constructor for one dataset, with shared inputs
+	// made outside the timed loop.
+	builder func(p P) func() L
+
+	len  func(l *L) int
+	read func(l *L, i int) int64
+}
+
+func (ly layout[P, L]) runBuild(b *testing.B, prefix string, p P) {
+	build := ly.builder(p)
+	b.Run(prefix+"/"+ly.subject, func(b *testing.B) {
+		b.ReportAllocs()
+		var l L
+		m := startGCMeter()
+		for b.Loop() {
+			l = build()
+		}
+		m.report(b)
+		l = *new(L)
+		b.ReportMetric(retainedObjects(func() { l = build() }), "heap-objs")
+		sinkInt = ly.len(&l)
+	})
+}
+
+func (ly layout[P, L]) runIterate(b *testing.B, prefix string, p P) {
+	build := ly.builder(p)
+	b.Run(prefix+"/"+ly.subject, func(b *testing.B) {
+		b.ReportAllocs()
+		var l L
+		objs := retainedObjects(func() { l = build() })
+		n := ly.len(&l)
+		var acc int64
+		m := startGCMeter()
+		for b.Loop() {
+			for j := 0; j < n; j++ {
+				acc += ly.read(&l, j)
+			}
+		}
+		m.report(b)
+		b.ReportMetric(objs, "heap-objs")
+		sinkI64 = acc
+	})
+}
+
+func (ly layout[P, L]) runBuildAndIterate(b *testing.B, prefix string, p P, reads int) {
+	build := ly.builder(p)
+	b.Run(prefix+"/"+ly.subject, func(b *testing.B) {
+		b.ReportAllocs()
+		var acc int64
+		m := startGCMeter()
+		for b.Loop() {
+			l := build()
+			n := ly.len(&l)
+			for range reads {
+				for j := 0; j < n; j++ {
+					acc += ly.read(&l, j)
+				}
+			}
+		}
+		m.report(b)
+		var last L
+		b.ReportMetric(retainedObjects(func() { last = build() }), "heap-objs")
+		sinkInt = ly.len(&last)
+		sinkI64 = acc
+	})
+}
+
+func (ly layout[P, L]) runIterateParallel(b *testing.B, prefix string, p P, procs int) {
+	var l L
+	objs := retainedObjects(func() { l = ly.builder(p)() })
+	n := ly.len(&l)
+	b.Run(prefix+"/"+ly.subject, func(b *testing.B) {
+		runParallel(b, procs, objs, func() int64 {
+			var acc int64
+			for j := 0; j < n; j++ {
+				acc += ly.read(&l, j)
+			}
+			return acc
+		})
+	})
+}
+
+// runParallel runs pass from every goroutine of b.RunParallel with GOMAXPROCS
+// set to procs, restoring the previous value afterwards. Each goroutine sums
+// into its own accumulator so the checksum itself is not contended. objs is
+// the heap-objs of the shared slice, measured when it was built.
+func runParallel(b *testing.B, procs int, objs float64, pass func() int64) {
+	defer runtime.GOMAXPROCS(runtime.GOMAXPROCS(procs))
+	b.ReportAllocs()
+	var total atomic.Int64
+	m := startGCMeter()
+	b.RunParallel(func(pb *testing.PB) {
+		var acc int64
+		for pb.Next() {
+			acc += pass()
+		}
+		total.Add(acc)
+	})
+	m.report(b)
+	b.ReportMetric(objs, "heap-objs")
+	sinkI64 = total.Load()
+}
+
+func (s pvSuite[P, T]) name(d dataset[P]) string {
+	if s.prefix != nil {
+		return s.prefix(d)
+	}
+	return d.name()
+}
+
+// sliceBuild registers a build run for each dataset.
+func (s pvSuite[P, T]) sliceBuild(b *testing.B, datasets []dataset[P]) {
+	for _, d := range datasets {
+		s.runBuild(b, s.name(d), d.p)
+	}
+}
+
+// sliceIterate registers an iterate run for each dataset.
+func (s pvSuite[P, T]) sliceIterate(b *testing.B, datasets []dataset[P]) {
+	for _, d := range datasets {
+		s.runIterate(b, s.name(d), d.p)
+	}
+}
+
+// seqIterate registers an iterate run for each dataset that puts
+// range-over-func iterators next to the plain range loops of sliceIterate.
+// See runSeqIterate for the subjects.
+func (s pvSuite[P, T]) seqIterate(b *testing.B, datasets []dataset[P]) {
+	for _, d := range datasets {
+		s.runSeqIterate(b, s.name(d), d.p)
+	}
+}
+
+// sliceIterateParallel registers an iterate run for each dataset and each
+// GOMAXPROCS value in procs. All reader goroutines share one slice, and each
+// op is one full pass over it.
+// Example prefix: "04096-Utxos-0034-Script-4-Procs".
+func (s pvSuite[P, T]) sliceIterateParallel(b *testing.B, datasets []dataset[P], procs []int) {
+	for _, d := range datasets {
+		values, pointers := s.builders(d.p)
+		var vals []T
+		var ptrs []*T
+		valObjs := retainedObjects(func() { vals = values() })
+		ptrObjs := retainedObjects(func() { ptrs = pointers() })
+		for _, n := range procs {
+			prefix := s.name(d) + fmt.Sprintf("-%d-Procs", n)
+			b.Run(prefix+"/0-Values", func(b *testing.B) {
+				runParallel(b, n, valObjs, func() int64 { return s.sumValues(vals) })
+			})
+			b.Run(prefix+"/1-Pointers", func(b *testing.B) {
+				runParallel(b, n, ptrObjs, func() int64 { return s.sumPointers(ptrs) })
+			})
+			for _, ly := range s.layouts {
+				ly.runIterateParallel(b, prefix, d.p, n)
+			}
+		}
+	}
+}
+
+// sliceBuildAndIterate registers build+iterate runs over d with 0 to reads-1
+// passes over the freshly built slice.
+func (s pvSuite[P, T]) sliceBuildAndIterate(b *testing.B, d dataset[P], reads int) {
+	values, pointers := s.builders(d.p)
+	for i := range reads {
+		prefix := d.name() + fmt.Sprintf("NReads%d", i)
+		b.Run(prefix+"/0-Values", func(b *testing.B) {
+			b.ReportAllocs()
+			var acc int64
+			m := startGCMeter()
+			for b.Loop() {
+				vals := values()
+				for range i {
+					acc += s.sumValues(vals)
+				}
+			}
+			m.report(b)
+			var last []T
+			b.ReportMetric(retainedObjects(func() { last = values() }), "heap-objs")
+			sinkInt = len(last)
+			sinkI64 = acc
+		})
+		b.Run(prefix+"/1-Pointers", func(b *testing.B) {
+			b.ReportAllocs()
+			var acc int64
+			m := startGCMeter()
+			for b.Loop() {
+				ptrs := pointers()
+				for range i {
+					acc += s.sumPointers(ptrs)
+				}
+			}
+			m.report(b)
+			var last []*T
+			b.ReportMetric(retainedObjects(func() { last = pointers() }), "heap-objs")
+			sinkInt = len(last)
+			sinkI64 = acc
+		})
+		for _, ly := range s.layouts {
+			ly.runBuildAndIterate(b, prefix, d.p, i)
+		}
+	}
+}
+
+// runBuild registers the build subjects for a single dataset.
+func (s pvSuite[P, T]) runBuild(b *testing.B, prefix string, p P) {
+	values, pointers := s.builders(p)
+	b.Run(prefix+"/0-Values", func(b *testing.B) {
+		b.ReportAllocs()
+		var vals []T
+		m := startGCMeter()
+		for b.Loop() {
+			vals = values()
+		}
+		m.report(b)
+		vals = nil
+		b.ReportMetric(retainedObjects(func() { vals = values() }), "heap-objs")
+		sinkInt = len(vals)
+	})
+	b.Run(prefix+"/1-Pointers", func(b *testing.B) {
+		b.ReportAllocs()
+		var ptrs []*T
+		m := startGCMeter()
+		for b.Loop() {
+			ptrs = pointers()
+		}
+		m.report(b)
+		ptrs = nil
+		b.ReportMetric(retainedObjects(func() { ptrs = pointers() }), "heap-objs")
+		sinkInt = len(ptrs)
+	})
+	for _, ly := range s.layouts {
+		ly.runBuild(b, prefix, p)
+	}
+}
+
+// runIterate registers the iterate subjects for a single dataset. Slices are
+// built once, outside the timed loop.
+func (s pvSuite[P, T]) runIterate(b *testing.B, prefix string, p P) {
+	values, pointers := s.builders(p)
+	b.Run(prefix+"/0-Values", func(b *testing.B) {
+		b.ReportAllocs()
+		var vals []T
+		objs := retainedObjects(func() { vals = values() })
+		var acc int64
+		m := startGCMeter()
+		for b.Loop() {
+			acc += s.sumValues(vals)
+		}
+		m.report(b)
+		b.ReportMetric(objs, "heap-objs")
+		sinkI64 = acc
+	})
+	b.Run(prefix+"/1-Pointers", func(b *testing.B) {
+		b.ReportAllocs()
+		var ptrs []*T
+		objs := retainedObjects(func() { ptrs = pointers() })
+		var acc int64
+		m := startGCMeter()
+		for b.Loop() {
+			acc += s.sumPointers(ptrs)
+		}
+		m.report(b)
+		b.ReportMetric(objs, "heap-objs")
+		sinkI64 = acc
+	})
+	for _, ly := range s.layouts {
+		ly.runIterate(b, prefix, p)
+	}
+}
+
+// runSeqIterate registers the iterator subjects for a single dataset. Slices
+// are built once, outside the timed loop, and every op makes its iterator,
+// as a call to an All method would.
+//   - "0-Values" and "1-Pointers" are the plain loops of runIterate.
+//   - "2-SeqValues" ranges over an iter.Seq[T] of the []T, copying every
+//     element into the yield callback.
+//   - "3-SeqAddrs" ranges over an iter.Seq[*T] of the []T.
+//   - "4-SeqPointers" ranges over an iter.Seq[*T] of the []*T.
+//   - "5-Seq2Pointers" ranges over an iter.Seq2[int, *T] of the []*T.
+func (s pvSuite[P, T]) runSeqIterate(b *testing.B, prefix string, p P) {
+	values, pointers := s.builders(p)
+	var vals []T
+	var ptrs []*T
+	valObjs := retainedObjects(func() { vals = values() })
+	ptrObjs := retainedObjects(func() { ptrs = pointers() })
+	run := func(subject string, objs float64, pass func() int64) {
+		b.Run(prefix+"/"+subject, func(b *testing.B) {
+			b.ReportAllocs()
+			var acc int64
+			m := startGCMeter()
+			for b.Loop() {
+				acc += pass()
+			}
+			m.report(b)
+			b.ReportMetric(objs, "heap-objs")
+			sinkI64 = acc
+		})
+	}
+	run("0-Values", valObjs, func() int64 { return s.sumValues(vals) })
+	run("1-Pointers", ptrObjs, func() int64 { return s.sumPointers(ptrs) })
+	// Each yielded copy is stored into cur, so read takes the address of
+	// one variable instead of escaping every copy.
+	var cur T
+	run("2-SeqValues", valObjs, func() int64 {
+		var acc int64
+		for cur = range seqValues(vals) {
+			acc += s.read(&cur)
+		}
+		return acc
+	})
+	run("3-SeqAddrs", valObjs, func() int64 {
+		var acc int64
+		for v := range seqAddrs(vals) {
+			acc += s.read(v)
+		}
+		return acc
+	})
+	run("4-SeqPointers", ptrObjs, func() int64 {
+		var acc int64
+		for ptr := range seqPointers(ptrs) {
+			acc += s.read(ptr)
+		}
+		return acc
+	})
+	run("5-Seq2Pointers", ptrObjs, func() int64 {
+		var acc int64
+		for j, ptr := range seqIndexed(ptrs) {
+			acc += s.read(ptr) + int64(j)
+		}
+		return acc
+	})
+}
+
+// seqValues yields a copy of every element of s.
+func seqValues[T any](s []T) iter.Seq[T] {
+	return func(yield func(T) bool) {
+		for i := range s {
+			if !yield(s[i]) {
+				return
+			}
+		}
+	}
+}
+
+// seqAddrs yields the address of every element of s.
+func seqAddrs[T any](s []T) iter.Seq[*T] {
+	return func(yield func(*T) bool) {
+		for i := range s {
+			if !yield(&s[i]) {
+				return
+			}
+		}
+	}
+}
+
+// seqPointers yields every pointer in s.
+func seqPointers[T any](s []*T) iter.Seq[*T] {
+	return func(yield func(*T) bool) {
+		for _, p := range s {
+			if !yield(p) {
+				return
+			}
+		}
+	}
+}
+
+// seqIndexed yields every index and pointer in s.
+func seqIndexed[T any](s []*T) iter.Seq2[int, *T] {
+	return func(yield func(int, *T) bool) {
+		for i, p := range s {
+			if !yield(i, p) {
+				return
+			}
+		}
+	}
+}
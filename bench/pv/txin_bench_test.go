@@ -1,164 +1,235 @@
 package pv
 
 import (
-    "fmt"
     "testing"
 
     "github.com/btcsuite/btcd/chaincfg/chainhash"
     "github.com/btcsuite/btcd/wire"
 )
 
-func makeTxInValue(i int, scriptSize int) wire.TxIn {
-    if scriptSize < 0 {
-        scriptSize = 0
-    }
+func makeTxInValue(i int, sigScript []byte, witness witnessShape) wire.TxIn {
     return wire.TxIn{
         PreviousOutPoint: wire.OutPoint{Hash: chainhash.Hash{byte(i % 251)}, Index: uint32(i)},
-        SignatureScript:  make([]byte, scriptSize),
-        Witness:          nil,
+        SignatureScript:  sigScript,
+        Witness:          makeWitness(witness),
         Sequence:         uint32(100000 + i),
     }
 }
 
-func makeTxInPointer(i int, scriptSize int) *wire.TxIn {
-    if scriptSize < 0 {
-        scriptSize = 0
-    }
+func makeTxInPointer(i int, sigScript []byte, witness witnessShape) *wire.TxIn {
     return &wire.TxIn{
         PreviousOutPoint: wire.OutPoint{Hash: chainhash.Hash{byte(i % 251)}, Index: uint32(i)},
-        SignatureScript:  make([]byte, scriptSize),
-        Witness:          nil,
+        SignatureScript:  sigScript,
+        Witness:          makeWitness(witness),
         Sequence:         uint32(100000 + i),
     }
 }
 
-func buildTxInValues(n, scriptSize int) []wire.TxIn {
+// buildTxInValues constructs a slice of TxIn values whose signature scripts
+// are allocated by alloc. Inputs with a witness get empty signature scripts,
+// otherwise they are len(sigScript) bytes.
+func buildTxInValues(n int, sigScript []byte, witness witnessShape, alloc scriptAlloc) []wire.TxIn {
+    size := sigScriptSize(len(sigScript), witness)
+    scripts := alloc.scripts(sigScript, n*size)
     s := make([]wire.TxIn, n)
     for i := 0; i < n; i++ {
-        s[i] = makeTxInValue(i, scriptSize)
+        s[i] = makeTxInValue(i, scripts.next(size), witness)
     }
     return s
 }
 
-func buildTxInPointers(n, scriptSize int) []*wire.TxIn {
+// buildTxInPointers constructs a slice of TxIn pointers whose signature
+// scripts are allocated by alloc.
+func buildTxInPointers(n int, sigScript []byte, witness witnessShape, alloc scriptAlloc) []*wire.TxIn {
+    size := sigScriptSize(len(sigScript), witness)
+    scripts := alloc.scripts(sigScript, n*size)
     s := make([]*wire.TxIn, n)
     for i := 0; i < n; i++ {
-        s[i] = makeTxInPointer(i, scriptSize)
+        s[i] = makeTxInPointer(i, scripts.next(size), witness)
     }
     return s
 }
 
+type txinParams struct {
+    numTxIns   int
+    scriptSize int
+    witness    witnessShape
+
+    // alloc is how signature scripts are allocated. The default makes one
+    // per element.
+    alloc scriptAlloc
+}
+
+// txinDims names the TxIn count and signature script size dimensions.
+// Example prefix: "04096-TxIns-0034-Sig".
+func txinDims(txinGrowth, scriptGrowth growthFunc) []dimension[txinParams] {
+    return []dimension[txinParams]{
+        {label: "TxIns", growth: txinGrowth, set: func(p *txinParams, v int) { p.numTxIns = v }},
+        {label: "Sig", growth: scriptGrowth, set: func(p *txinParams, v int) { p.scriptSize = v }},
+    }
+}
+
+// txinAllocDims is txinDims with a script allocation dimension.
+// Example prefix: "04096-TxIns-0034-Sig-Arena".
+func txinAllocDims(txinGrowth, scriptGrowth growthFunc) []dimension[txinParams] {
+    return append(txinDims(txinGrowth, scriptGrowth),
+        allocDim(func(p *txinParams, a scriptAlloc) { p.alloc = a }))
+}
+
+// txinWitnessDims names the TxIn count and witness dimensions. Inputs with a
+// witness carry no signature script. Example prefix:
+// "04096-TxIns-P2WPKH-Witness".
+func txinWitnessDims(txinGrowth growthFunc) []dimension[txinParams] {
+    return []dimension[txinParams]{
+        {label: "TxIns", growth: txinGrowth, set: func(p *txinParams, v int) { p.numTxIns = v }},
+        witnessDim(func(p *txinParams, w witnessShape) { p.witness = w }),
+    }
+}
+
+// txinWitnessGrowthDims names the TxIn count and the witness item count and
+// size dimensions. Example prefix: "1024-TxIns-4-WitnessItems-128-ItemBytes".
+func txinWitnessGrowthDims(txinGrowth, countGrowth, sizeGrowth growthFunc) []dimension[txinParams] {
+    return append([]dimension[txinParams]{
+        {label: "TxIns", growth: txinGrowth, set: func(p *txinParams, v int) { p.numTxIns = v }},
+    }, witnessGrowthDims(func(p *txinParams) *witnessShape { return &p.witness }, countGrowth, sizeGrowth)...)
+}
+
+// readTxIn is the accessor summed by every TxIn iterate benchmark.
+func readTxIn(ti *wire.TxIn) int64 {
+    return int64(len(ti.SignatureScript)) +
+        int64(ti.Sequence) +
+        int64(ti.PreviousOutPoint.Index) +
+        int64(ti.PreviousOutPoint.Hash[0]) +
+        readWitness(ti.Witness)
+}
+
+// sumTxInValues is readTxIn over a []wire.TxIn.
+func sumTxInValues(vals []wire.TxIn) int64 {
+    var acc int64
+    for _, val := range vals {
+        acc += readTxIn(&val)
+    }
+    return acc
+}
+
+// sumTxInPointers is readTxIn over a []*wire.TxIn.
+func sumTxInPointers(ptrs []*wire.TxIn) int64 {
+    var acc int64
+    for _, ptr := range ptrs {
+        acc += readTxIn(ptr)
+    }
+    return acc
+}
+
+var txinSuite = pvSuite[txinParams, wire.TxIn]{
+    builders: func(p txinParams) (func() []wire.TxIn, func() []*wire.TxIn) {
+        sigScript := makePkScript(p.scriptSize)
+        alloc := p.alloc.or(allocPerElement)
+        return func() []wire.TxIn { return buildTxInValues(p.numTxIns, sigScript, p.witness, alloc) },
+            func() []*wire.TxIn { return buildTxInPointers(p.numTxIns, sigScript, p.witness, alloc) }
+    },
+    read:        readTxIn,
+    sumValues:   sumTxInValues,
+    sumPointers: sumTxInPointers,
+}
+
+// txinDatasets is the standard lock-step TxIn progression.
+func txinDatasets() []dataset[txinParams] {
+    return generateDatasets(benchConfig[txinParams]{
+        dims:       txinDims(scaleGrowth(8, exponentialGrowth()), linearGrowth(34)),
+        iterations: 8,
+    })
+}
+
+// txinWitnessDatasets is the grid of 8..1024 TxIns against every witness
+// preset.
+func txinWitnessDatasets() []dataset[txinParams] {
+    return generateSweep(sweepConfig[txinParams]{
+        dims:       txinWitnessDims(scaleGrowth(8, exponentialGrowth())),
+        iterations: 8,
+    })
+}
+
+// txinWitnessGrowthDatasets is the grid of 1..8 witness items against 32..256
+// byte items, at 1024 TxIns.
+func txinWitnessGrowthDatasets() []dataset[txinParams] {
+    dims := txinWitnessGrowthDims(constantGrowth(1024), exponentialGrowth(), scaleGrowth(32, exponentialGrowth()))
+    dims[0].steps = 1
+    return generateSweep(sweepConfig[txinParams]{
+        dims:       dims,
+        iterations: 4,
+    })
+}
+
+// txinAllocDatasets is the grid of 8..1024 TxIns with 34-byte signature
+// scripts under every script allocation strategy.
+func txinAllocDatasets() []dataset[txinParams] {
+    dims := txinAllocDims(scaleGrowth(8, exponentialGrowth()), constantGrowth(34))
+    dims[1].steps = 1
+    return generateSweep(sweepConfig[txinParams]{
+        dims:       dims,
+        iterations: 8,
+    })
+}
+
 // BenchmarkTxIn_SliceBuild benchmarks building slices of TxIn values vs pointers
 func BenchmarkTxIn_SliceBuild(b *testing.B) {
-    datasets := generateTxInDatasets(txinBenchConfig{
-        txinGrowth:   scaleGrowth(8, exponentialGrowth()),
-        scriptGrowth: linearGrowth(34),
-        iterations:   8,
-    })
-    for _, d := range datasets {
-        prefix := d.name()
-        b.Run(prefix+"/0-Values", func(b *testing.B) {
-            b.ReportAllocs()
-            var s []wire.TxIn
-            for b.Loop() {
-                s = buildTxInValues(d.numTxIns, d.scriptSize)
-            }
-            sinkInt = len(s)
-        })
-        b.Run(prefix+"/1-Pointers", func(b *testing.B) {
-            b.ReportAllocs()
-            var s []*wire.TxIn
-            for b.Loop() {
-                s = buildTxInPointers(d.numTxIns, d.scriptSize)
-            }
-            sinkInt = len(s)
-        })
-    }
+    txinSuite.sliceBuild(b, txinDatasets())
 }
 
 // BenchmarkTxIn_SliceIterate benchmarks iterating over slices of TxIn values vs pointers
 func BenchmarkTxIn_SliceIterate(b *testing.B) {
-    datasets := generateTxInDatasets(txinBenchConfig{
-        txinGrowth:   scaleGrowth(8, exponentialGrowth()),
-        scriptGrowth: linearGrowth(34),
-        iterations:   8,
-    })
-    for _, d := range datasets {
-        vals := buildTxInValues(d.numTxIns, d.scriptSize)
-        ptrs := buildTxInPointers(d.numTxIns, d.scriptSize)
-        prefix := d.name()
-        b.Run(prefix+"/0-Values", func(b *testing.B) {
-            b.ReportAllocs()
-            var acc int64
-            for b.Loop() {
-                for _, val := range vals {
-                    acc += int64(len(val.SignatureScript))
-                    acc += int64(val.Sequence)
-                    acc += int64(val.PreviousOutPoint.Index)
-                    acc += int64(val.PreviousOutPoint.Hash[0])
-                }
-            }
-            sinkI64 = acc
-        })
-        b.Run(prefix+"/1-Pointers", func(b *testing.B) {
-            b.ReportAllocs()
-            var acc int64
-            for b.Loop() {
-                for _, ptr := range ptrs {
-                    acc += int64(len(ptr.SignatureScript))
-                    acc += int64(ptr.Sequence)
-                    acc += int64(ptr.PreviousOutPoint.Index)
-                    acc += int64(ptr.PreviousOutPoint.Hash[0])
-                }
-            }
-            sinkI64 = acc
-        })
-    }
+    txinSuite.sliceIterate(b, txinDatasets())
+}
+
+// BenchmarkTxIn_SeqIterate benchmarks iter.Seq and iter.Seq2 iterators over
+// slices of TxIn values vs pointers next to the plain range loops.
+func BenchmarkTxIn_SeqIterate(b *testing.B) {
+    txinSuite.seqIterate(b, txinDatasets())
+}
+
+// BenchmarkTxIn_WitnessSliceBuild benchmarks building slices of segwit TxIn
+// values vs pointers, allocating every witness item.
+func BenchmarkTxIn_WitnessSliceBuild(b *testing.B) {
+    txinSuite.sliceBuild(b, txinWitnessDatasets())
+}
+
+// BenchmarkTxIn_WitnessSliceIterate benchmarks iterating over slices of
+// segwit TxIn values vs pointers, reading every witness item.
+func BenchmarkTxIn_WitnessSliceIterate(b *testing.B) {
+    txinSuite.sliceIterate(b, txinWitnessDatasets())
+}
+
+// BenchmarkTxIn_WitnessGrowthSliceBuild benchmarks building slices of segwit
+// TxIn values vs pointers as the witness item count and size grow.
+func BenchmarkTxIn_WitnessGrowthSliceBuild(b *testing.B) {
+    txinSuite.sliceBuild(b, txinWitnessGrowthDatasets())
+}
+
+// BenchmarkTxIn_WitnessGrowthSliceIterate benchmarks iterating over slices of
+// segwit TxIn values vs pointers as the witness item count and size grow.
+func BenchmarkTxIn_WitnessGrowthSliceIterate(b *testing.B) {
+    txinSuite.sliceIterate(b, txinWitnessGrowthDatasets())
+}
+
+// BenchmarkTxIn_ScriptAllocSliceBuild benchmarks building slices of TxIn
+// values vs pointers with shared, per-element and arena-backed signature
+// scripts.
+func BenchmarkTxIn_ScriptAllocSliceBuild(b *testing.B) {
+    txinSuite.sliceBuild(b, txinAllocDatasets())
+}
+
+// BenchmarkTxIn_ScriptAllocSliceIterate benchmarks iterating over slices of
+// TxIn values vs pointers with shared, per-element and arena-backed
+// signature scripts.
+func BenchmarkTxIn_ScriptAllocSliceIterate(b *testing.B) {
+    txinSuite.sliceIterate(b, txinAllocDatasets())
 }
 
 // BenchmarkTxIn_SliceBuildAndIterate benchmarks building and iterating over slices
 // of TxIn values vs pointers with repeated reads.
 func BenchmarkTxIn_SliceBuildAndIterate(b *testing.B) {
-    d := txinDataset{
-        numTxIns:   128,
-        scriptSize: 64,
-        maxTxIns:   128,
-        maxScript:  64,
-    }
-    for i := range 10 {
-        prefix := d.name() + fmt.Sprintf("NReads%d", i)
-        b.Run(prefix+"/0-Values", func(b *testing.B) {
-            b.ReportAllocs()
-            var acc int64
-            for b.Loop() {
-                vals := buildTxInValues(d.numTxIns, d.scriptSize)
-                for range i {
-                    for _, val := range vals {
-                        acc += int64(len(val.SignatureScript))
-                        acc += int64(val.Sequence)
-                        acc += int64(val.PreviousOutPoint.Index)
-                        acc += int64(val.PreviousOutPoint.Hash[0])
-                    }
-                }
-            }
-            sinkI64 = acc
-        })
-        b.Run(prefix+"/1-Pointers", func(b *testing.B) {
-            b.ReportAllocs()
-            var acc int64
-            for b.Loop() {
-                ptrs := buildTxInPointers(d.numTxIns, d.scriptSize)
-                for range i {
-                    for _, ptr := range ptrs {
-                        acc += int64(len(ptr.SignatureScript))
-                        acc += int64(ptr.Sequence)
-                        acc += int64(ptr.PreviousOutPoint.Index)
-                        acc += int64(ptr.PreviousOutPoint.Hash[0])
-                    }
-                }
-            }
-            sinkI64 = acc
-        })
-    }
+    d := singleDataset(txinDims(constantGrowth(128), constantGrowth(64)))
+    txinSuite.sliceBuildAndIterate(b, d, 10)
 }
 
@@ -1,147 +1,266 @@
 package pv
 
 import (
-    "fmt"
+    "bytes"
     "testing"
 
     "github.com/btcsuite/btcd/wire"
 )
 
-func makeTxOutValue(i int, scriptSize int) wire.TxOut {
-    if scriptSize < 0 {
-        scriptSize = 0
-    }
+func makeTxOutValue(i int, pkScript []byte) wire.TxOut {
     return wire.TxOut{
         Value:    int64(1000 + i),
-        PkScript: make([]byte, scriptSize),
+        PkScript: pkScript,
     }
 }
 
-func makeTxOutPointer(i int, scriptSize int) *wire.TxOut {
-    if scriptSize < 0 {
-        scriptSize = 0
-    }
+func makeTxOutPointer(i int, pkScript []byte) *wire.TxOut {
     return &wire.TxOut{
         Value:    int64(1000 + i),
-        PkScript: make([]byte, scriptSize),
+        PkScript: pkScript,
     }
 }
 
-func buildTxOutValues(n, scriptSize int) []wire.TxOut {
+// buildTxOutValues constructs a slice of TxOut values whose len(pkScript)
+// byte scripts are allocated by alloc.
+func buildTxOutValues(n int, pkScript []byte, alloc scriptAlloc) []wire.TxOut {
+    scripts := alloc.scripts(pkScript, n*len(pkScript))
     s := make([]wire.TxOut, n)
     for i := 0; i < n; i++ {
-        s[i] = makeTxOutValue(i, scriptSize)
+        s[i] = makeTxOutValue(i, scripts.next(len(pkScript)))
     }
     return s
 }
 
-func buildTxOutPointers(n, scriptSize int) []*wire.TxOut {
+// buildTxOutPointers constructs a slice of TxOut pointers whose scripts are
+// allocated by alloc.
+func buildTxOutPointers(n int, pkScript []byte, alloc scriptAlloc) []*wire.TxOut {
+    scripts := alloc.scripts(pkScript, n*len(pkScript))
     s := make([]*wire.TxOut, n)
     for i := 0; i < n; i++ {
-        s[i] = makeTxOutPointer(i, scriptSize)
+        s[i] = makeTxOutPointer(i, scripts.next(len(pkScript)))
     }
     return s
 }
 
+// buildTxOutValuesScripts constructs a slice of TxOut values, one per script.
+// Each element gets its own copy of its script, as buildTxOutValues allocates
+// one per element.
+func buildTxOutValuesScripts(scripts [][]byte) []wire.TxOut {
+    s := make([]wire.TxOut, len(scripts))
+    for i, pkScript := range scripts {
+        s[i] = wire.TxOut{Value: int64(1000 + i), PkScript: bytes.Clone(pkScript)}
+    }
+    return s
+}
+
+// buildTxOutPointersScripts constructs a slice of TxOut pointers, one per
+// script, each with its own copy of the script.
+func buildTxOutPointersScripts(scripts [][]byte) []*wire.TxOut {
+    s := make([]*wire.TxOut, len(scripts))
+    for i, pkScript := range scripts {
+        s[i] = &wire.TxOut{Value: int64(1000 + i), PkScript: bytes.Clone(pkScript)}
+    }
+    return s
+}
+
+// txoutColumns is the struct-of-arrays layout of a []wire.TxOut.
+type txoutColumns struct {
+    Values    []int64
+    PkScripts [][]byte
+}
+
+func buildTxOutColumns(n int, pkScript []byte, alloc scriptAlloc) txoutColumns {
+    scripts := alloc.scripts(pkScript, n*len(pkScript))
+    c := txoutColumns{
+        Values:    make([]int64, n),
+        PkScripts: make([][]byte, n),
+    }
+    for i := 0; i < n; i++ {
+        c.Values[i] = int64(1000 + i)
+        c.PkScripts[i] = scripts.next(len(pkScript))
+    }
+    return c
+}
+
+type txoutParams struct {
+    numTxOuts  int
+    scriptSize int
+
+    // mix selects generated output scripts for the script type datasets;
+    // scriptSize is unused there.
+    mix scriptMix
+
+    // alloc is how scripts are allocated. The default makes one per
+    // element.
+    alloc scriptAlloc
+}
+
+// txoutDims names the TxOut count and script size dimensions.
+// Example prefix: "04096-TxOuts-0034-Script".
+func txoutDims(txoutGrowth, scriptGrowth growthFunc) []dimension[txoutParams] {
+    return []dimension[txoutParams]{
+        {label: "TxOuts", growth: txoutGrowth, set: func(p *txoutParams, v int) { p.numTxOuts = v }},
+        {label: "Script", growth: scriptGrowth, set: func(p *txoutParams, v int) { p.scriptSize = v }},
+    }
+}
+
+// txoutAllocDims is txoutDims with a script allocation dimension.
+// Example prefix: "04096-TxOuts-0034-Script-Arena".
+func txoutAllocDims(txoutGrowth, scriptGrowth growthFunc) []dimension[txoutParams] {
+    return append(txoutDims(txoutGrowth, scriptGrowth),
+        allocDim(func(p *txoutParams, a scriptAlloc) { p.alloc = a }))
+}
+
+// txoutScriptDims names the TxOut count and script type dimensions.
+// Example prefix: "04096-TxOuts-P2TR".
+func txoutScriptDims(txoutGrowth growthFunc) []dimension[txoutParams] {
+    return []dimension[txoutParams]{
+        {label: "TxOuts", growth: txoutGrowth, set: func(p *txoutParams, v int) { p.numTxOuts = v }},
+        scriptDim(func(p *txoutParams, m scriptMix) { p.mix = m }),
+    }
+}
+
+// readTxOut is the accessor summed by every TxOut iterate benchmark.
+func readTxOut(to *wire.TxOut) int64 {
+    return to.Value + int64(len(to.PkScript))
+}
+
+// sumTxOutValues is readTxOut over a []wire.TxOut.
+func sumTxOutValues(vals []wire.TxOut) int64 {
+    var acc int64
+    for _, val := range vals {
+        acc += readTxOut(&val)
+    }
+    return acc
+}
+
+// sumTxOutPointers is readTxOut over a []*wire.TxOut.
+func sumTxOutPointers(ptrs []*wire.TxOut) int64 {
+    var acc int64
+    for _, ptr := range ptrs {
+        acc += readTxOut(ptr)
+    }
+    return acc
+}
+
+var txoutSuite = pvSuite[txoutParams, wire.TxOut]{
+    builders: func(p txoutParams) (func() []wire.TxOut, func() []*wire.TxOut) {
+        pkScript := makePkScript(p.scriptSize)
+        alloc := p.alloc.or(allocPerElement)
+        return func() []wire.TxOut { return buildTxOutValues(p.numTxOuts, pkScript, alloc) },
+            func() []*wire.TxOut { return buildTxOutPointers(p.numTxOuts, pkScript, alloc) }
+    },
+    read:        readTxOut,
+    sumValues:   sumTxOutValues,
+    sumPointers: sumTxOutPointers,
+    layouts: []pvLayout[txoutParams]{
+        layout[txoutParams, txoutColumns]{
+            subject: "2-SoA",
+            builder: func(p txoutParams) func() txoutColumns {
+                pkScript := makePkScript(p.scriptSize)
+                alloc := p.alloc.or(allocPerElement)
+                return func() txoutColumns { return buildTxOutColumns(p.numTxOuts, pkScript, alloc) }
+            },
+            len: func(c *txoutColumns) int { return len(c.Values) },
+            read: func(c *txoutColumns, i int) int64 {
+                return c.Values[i] + int64(len(c.PkScripts[i]))
+            },
+        },
+    },
+}
+
+// txoutDatasets is the standard lock-step TxOut progression.
+func txoutDatasets() []dataset[txoutParams] {
+    return generateDatasets(benchConfig[txoutParams]{
+        dims:       txoutDims(scaleGrowth(8, exponentialGrowth()), linearGrowth(34)),
+        iterations: 8,
+    })
+}
+
 // BenchmarkTxOut_SliceBuild benchmarks building slices of TxOut values vs pointers
 func BenchmarkTxOut_SliceBuild(b *testing.B) {
-    datasets := generateTxOutDatasets(txoutBenchConfig{
-        txoutGrowth:  scaleGrowth(8, exponentialGrowth()),
-        scriptGrowth: linearGrowth(34),
-        iterations:   8,
-    })
-    for _, d := range datasets {
-        prefix := d.name()
-        b.Run(prefix+"/0-Values", func(b *testing.B) {
-            b.ReportAllocs()
-            var s []wire.TxOut
-            for b.Loop() {
-                s = buildTxOutValues(d.numTxOuts, d.scriptSize)
-            }
-            sinkInt = len(s)
-        })
-        b.Run(prefix+"/1-Pointers", func(b *testing.B) {
-            b.ReportAllocs()
-            var s []*wire.TxOut
-            for b.Loop() {
-                s = buildTxOutPointers(d.numTxOuts, d.scriptSize)
-            }
-            sinkInt = len(s)
-        })
-    }
+    txoutSuite.sliceBuild(b, txoutDatasets())
 }
 
 // BenchmarkTxOut_SliceIterate benchmarks iterating over slices of TxOut values vs pointers
 func BenchmarkTxOut_SliceIterate(b *testing.B) {
-    datasets := generateTxOutDatasets(txoutBenchConfig{
-        txoutGrowth:  scaleGrowth(8, exponentialGrowth()),
-        scriptGrowth: linearGrowth(34),
-        iterations:   8,
+    txoutSuite.sliceIterate(b, txoutDatasets())
+}
+
+// BenchmarkTxOut_SeqIterate benchmarks iter.Seq and iter.Seq2 iterators over
+// slices of TxOut values vs pointers next to the plain range loops.
+func BenchmarkTxOut_SeqIterate(b *testing.B) {
+    txoutSuite.seqIterate(b, txoutDatasets())
+}
+
+// BenchmarkTxOut_SliceIterateParallel benchmarks concurrent readers sharing one
+// slice of TxOut values vs pointers at GOMAXPROCS 1, 2, 4 and 8.
+func BenchmarkTxOut_SliceIterateParallel(b *testing.B) {
+    txoutSuite.sliceIterateParallel(b, txoutDatasets(), parallelProcs)
+}
+
+// txoutAllocDatasets is the grid of 8..1024 TxOuts with 34-byte scripts
+// under every script allocation strategy.
+func txoutAllocDatasets() []dataset[txoutParams] {
+    dims := txoutAllocDims(scaleGrowth(8, exponentialGrowth()), constantGrowth(34))
+    dims[1].steps = 1
+    return generateSweep(sweepConfig[txoutParams]{
+        dims:       dims,
+        iterations: 8,
     })
-    for _, d := range datasets {
-        vals := buildTxOutValues(d.numTxOuts, d.scriptSize)
-        ptrs := buildTxOutPointers(d.numTxOuts, d.scriptSize)
-        prefix := d.name()
-        b.Run(prefix+"/0-Values", func(b *testing.B) {
-            b.ReportAllocs()
-            var acc int64
-            for b.Loop() {
-                for _, val := range vals {
-                    acc += val.Value + int64(len(val.PkScript))
-                }
-            }
-            sinkI64 = acc
-        })
-        b.Run(prefix+"/1-Pointers", func(b *testing.B) {
-            b.ReportAllocs()
-            var acc int64
-            for b.Loop() {
-                for _, ptr := range ptrs {
-                    acc += ptr.Value + int64(len(ptr.PkScript))
-                }
-            }
-            sinkI64 = acc
-        })
-    }
+}
+
+// BenchmarkTxOut_ScriptAllocSliceBuild benchmarks building slices of TxOut
+// values vs pointers with shared, per-element and arena-backed scripts.
+func BenchmarkTxOut_ScriptAllocSliceBuild(b *testing.B) {
+    txoutSuite.sliceBuild(b, txoutAllocDatasets())
+}
+
+// BenchmarkTxOut_ScriptAllocSliceIterate benchmarks iterating over slices of
+// TxOut values vs pointers with shared, per-element and arena-backed scripts.
+func BenchmarkTxOut_ScriptAllocSliceIterate(b *testing.B) {
+    txoutSuite.sliceIterate(b, txoutAllocDatasets())
+}
+
+// txoutScriptSuite builds TxOuts whose pkScripts are real standard scripts
+// from the dataset's script mix. The scripts are generated once per dataset;
+// the builders only copy them.
+var txoutScriptSuite = pvSuite[txoutParams, wire.TxOut]{
+    builders: func(p txoutParams) (func() []wire.TxOut, func() []*wire.TxOut) {
+        scripts := p.mix.scripts(p.numTxOuts)
+        return func() []wire.TxOut { return buildTxOutValuesScripts(scripts) },
+            func() []*wire.TxOut { return buildTxOutPointersScripts(scripts) }
+    },
+    read:        readTxOut,
+    sumValues:   sumTxOutValues,
+    sumPointers: sumTxOutPointers,
+}
+
+// txoutScriptDatasets is the grid of 8..1024 TxOuts against every script mix.
+func txoutScriptDatasets() []dataset[txoutParams] {
+    return generateSweep(sweepConfig[txoutParams]{
+        dims:       txoutScriptDims(scaleGrowth(8, exponentialGrowth())),
+        iterations: 8,
+    })
+}
+
+// BenchmarkTxOut_ScriptTypeSliceBuild benchmarks building slices of TxOut
+// values vs pointers that hold real standard scripts.
+func BenchmarkTxOut_ScriptTypeSliceBuild(b *testing.B) {
+    txoutScriptSuite.sliceBuild(b, txoutScriptDatasets())
+}
+
+// BenchmarkTxOut_ScriptTypeSliceIterate benchmarks iterating over slices of
+// TxOut values vs pointers that hold real standard scripts.
+func BenchmarkTxOut_ScriptTypeSliceIterate(b *testing.B) {
+    txoutScriptSuite.sliceIterate(b, txoutScriptDatasets())
 }
 
 // BenchmarkTxOut_SliceBuildAndIterate benchmarks building and iterating over slices
 // of TxOut values vs pointers with repeated reads.
 func BenchmarkTxOut_SliceBuildAndIterate(b *testing.B) {
-    d := txoutDataset{
-        numTxOuts:  128,
-        scriptSize: 64,
-        maxTxOuts:  128,
-        maxScript:  64,
-    }
-    for i := range 10 {
-        prefix := d.name() + fmt.Sprintf("NReads%d", i)
-        b.Run(prefix+"/0-Values", func(b *testing.B) {
-            b.ReportAllocs()
-            var acc int64
-            for b.Loop() {
-                vals := buildTxOutValues(d.numTxOuts, d.scriptSize)
-                for range i {
-                    for _, val := range vals {
-                        acc += val.Value + int64(len(val.PkScript))
-                    }
-                }
-            }
-            sinkI64 = acc
-        })
-        b.Run(prefix+"/1-Pointers", func(b *testing.B) {
-            b.ReportAllocs()
-            var acc int64
-            for b.Loop() {
-                ptrs := buildTxOutPointers(d.numTxOuts, d.scriptSize)
-                for range i {
-                    for _, ptr := range ptrs {
-                        acc += ptr.Value + int64(len(ptr.PkScript))
-                    }
-                }
-            }
-            sinkI64 = acc
-        })
-    }
+    d := singleDataset(txoutDims(constantGrowth(128), constantGrowth(64)))
+    txoutSuite.sliceBuildAndIterate(b, d, 10)
 }
 
@@ -1,7 +1,6 @@
 package pv
 
 import (
-	"fmt"
 	"testing"
 
 	"github.com/btcsuite/btcd/btcutil"
@@ -88,120 +87,333 @@ func buildUtxoPointers(n int, pkScript []byte) []*Utxo {
 	return s
 }
 
+// buildUtxoValuesScripts constructs a slice of Utxo values, one per script.
+// Element i shares scripts[i].
+func buildUtxoValuesScripts(scripts [][]byte) []Utxo {
+	var s []Utxo
+	for i, pkScript := range scripts {
+		s = append(s, makeUtxoValue(i, pkScript))
+	}
+	return s
+}
+
+// buildUtxoPointersScripts constructs a slice of Utxo pointers, one per
+// script. Element i shares scripts[i].
+func buildUtxoPointersScripts(scripts [][]byte) []*Utxo {
+	var s []*Utxo
+	for i, pkScript := range scripts {
+		s = append(s, makeUtxoPointer(i, pkScript))
+	}
+	return s
+}
+
+// buildUtxoValuesAlloc is buildUtxoValues with the scripts allocated by
+// alloc. Every script is len(pkScript) bytes.
+func buildUtxoValuesAlloc(n int, pkScript []byte, alloc scriptAlloc) []Utxo {
+	scripts := alloc.scripts(pkScript, n*len(pkScript))
+	var s []Utxo
+	for i := 0; i < n; i++ {
+		s = append(s, makeUtxoValue(i, scripts.next(len(pkScript))))
+	}
+	return s
+}
+
+// buildUtxoPointersAlloc is buildUtxoPointers with the scripts allocated by
+// alloc.
+func buildUtxoPointersAlloc(n int, pkScript []byte, alloc scriptAlloc) []*Utxo {
+	scripts := alloc.scripts(pkScript, n*len(pkScript))
+	var s []*Utxo
+	for i := 0; i < n; i++ {
+		s = append(s, makeUtxoPointer(i, scripts.next(len(pkScript))))
+	}
+	return s
+}
+
+// buildUtxoSlab constructs a slice of Utxo pointers that all point into one
+// pre-allocated []Utxo block, so the set costs two allocations instead of one
+// per element, plus whatever alloc spends on scripts.
+func buildUtxoSlab(n int, pkScript []byte, alloc scriptAlloc) []*Utxo {
+	scripts := alloc.scripts(pkScript, n*len(pkScript))
+	slab := make([]Utxo, n)
+	s := make([]*Utxo, n)
+	for i := range slab {
+		slab[i] = makeUtxoValue(i, scripts.next(len(pkScript)))
+		s[i] = &slab[i]
+	}
+	return s
+}
+
+// readUtxo is the accessor summed by every Utxo iterate benchmark.
+func readUtxo(u *Utxo) int64 {
+	return int64(u.Amount) + int64(len(u.PkScript)) + int64(u.Confirmations)
+}
+
+// sumUtxoValues is readUtxo over a []Utxo. It reads the fields of val
+// directly: passing &val to readUtxo makes the compiler copy every Utxo in
+// full, which the original loop never did.
+func sumUtxoValues(vals []Utxo) int64 {
+	var acc int64
+	for _, val := range vals {
+		acc += int64(val.Amount) + int64(len(val.PkScript)) + int64(val.Confirmations)
+	}
+	return acc
+}
+
+// sumUtxoPointers is readUtxo over a []*Utxo.
+func sumUtxoPointers(ptrs []*Utxo) int64 {
+	var acc int64
+	for _, ptr := range ptrs {
+		acc += readUtxo(ptr)
+	}
+	return acc
+}
+
+// utxoColumns is the struct-of-arrays layout of a []Utxo: one slice per
+// field, all of the same length.
+type utxoColumns struct {
+	OutPoints     []wire.OutPoint
+	Amounts       []btcutil.Amount
+	PkScripts     [][]byte
+	Confirmations []int32
+	Spendable     []bool
+	Addresses     []btcutil.Address
+	Accounts      []string
+	AddressTypes  []waddrmgr.AddressType
+	Locked        []bool
+}
+
+// buildUtxoColumns constructs the columnar layout of buildUtxoValuesAlloc.
+func buildUtxoColumns(n int, pkScript []byte, alloc scriptAlloc) utxoColumns {
+	scripts := alloc.scripts(pkScript, n*len(pkScript))
+	c := utxoColumns{
+		OutPoints:     make([]wire.OutPoint, n),
+		Amounts:       make([]btcutil.Amount, n),
+		PkScripts:     make([][]byte, n),
+		Confirmations: make([]int32, n),
+		Spendable:     make([]bool, n),
+		Addresses:     make([]btcutil.Address, n),
+		Accounts:      make([]string, n),
+		AddressTypes:  make([]waddrmgr.AddressType, n),
+		Locked:        make([]bool, n),
+	}
+	for i := 0; i < n; i++ {
+		c.OutPoints[i] = wire.OutPoint{Hash: chainhash.Hash{byte(i % 251)}, Index: uint32(i)}
+		c.Amounts[i] = btcutil.Amount(1000 + i)
+		c.PkScripts[i] = scripts.next(len(pkScript))
+		c.Confirmations[i] = int32(i % 100)
+		c.Spendable[i] = i%2 == 0
+		c.Accounts[i] = "default"
+		c.AddressTypes[i] = waddrmgr.WitnessPubKey
+	}
+	return c
+}
+
+type utxoParams struct {
+	numUtxos   int
+	scriptSize int
+
+	// mix selects generated output scripts for the script type datasets;
+	// scriptSize is unused there.
+	mix scriptMix
+
+	// alloc is how scripts are allocated. The default shares one.
+	alloc scriptAlloc
+}
+
+// utxoDims names the Utxo count and script size dimensions.
+// Example prefix: "04096-Utxos-0034-Script".
+func utxoDims(utxoGrowth, scriptGrowth growthFunc) []dimension[utxoParams] {
+	return []dimension[utxoParams]{
+		{label: "Utxos", growth: utxoGrowth, set: func(p *utxoParams, v int) { p.numUtxos = v }},
+		{label: "Script", growth: scriptGrowth, set: func(p *utxoParams, v int) { p.scriptSize = v }},
+	}
+}
+
+// utxoAllocDims is utxoDims with a script allocation dimension.
+// Example prefix: "04096-Utxos-0034-Script-Arena".
+func utxoAllocDims(utxoGrowth, scriptGrowth growthFunc) []dimension[utxoParams] {
+	return append(utxoDims(utxoGrowth, scriptGrowth),
+		allocDim(func(p *utxoParams, a scriptAlloc) { p.alloc = a }))
+}
+
+// utxoScriptDims names the Utxo count and script type dimensions.
+// Example prefix: "04096-Utxos-P2WPKH".
+func utxoScriptDims(utxoGrowth growthFunc) []dimension[utxoParams] {
+	return []dimension[utxoParams]{
+		{label: "Utxos", growth: utxoGrowth, set: func(p *utxoParams, v int) { p.numUtxos = v }},
+		scriptDim(func(p *utxoParams, m scriptMix) { p.mix = m }),
+	}
+}
+
+// makePkScript returns a script of n bytes filled with a fixed pattern. A
+// negative n gives an empty script.
+func makePkScript(n int) []byte {
+	n = max(n, 0)
+	pkScript := make([]byte, n)
+	for j := 0; j < n; j++ {
+		pkScript[j] = byte(j)
+	}
+	return pkScript
+}
+
+// utxoSuite builds Utxos that share one pkScript per dataset unless the
+// dataset picks another script allocation. Sharing isolates the benchmark to
+// the cost of building the slice of structs, not the cost of building the
+// scripts themselves.
+var utxoSuite = pvSuite[utxoParams, Utxo]{
+	builders: func(p utxoParams) (func() []Utxo, func() []*Utxo) {
+		pkScript := makePkScript(p.scriptSize)
+		alloc := p.alloc.or(allocShared)
+		return func() []Utxo { return buildUtxoValuesAlloc(p.numUtxos, pkScript, alloc) },
+			func() []*Utxo { return buildUtxoPointersAlloc(p.numUtxos, pkScript, alloc) }
+	},
+	read:        readUtxo,
+	sumValues:   sumUtxoValues,
+	sumPointers: sumUtxoPointers,
+	layouts: []pvLayout[utxoParams]{
+		layout[utxoParams, utxoColumns]{
+			subject: "2-SoA",
+			builder: func(p utxoParams) func() utxoColumns {
+				pkScript := makePkScript(p.scriptSize)
+				alloc := p.alloc.or(allocShared)
+				return func() utxoColumns { return buildUtxoColumns(p.numUtxos, pkScript, alloc) }
+			},
+			len: func(c *utxoColumns) int { return len(c.Amounts) },
+			read: func(c *utxoColumns, i int) int64 {
+				return int64(c.Amounts[i]) + int64(len(c.PkScripts[i])) + int64(c.Confirmations[i])
+			},
+		},
+		layout[utxoParams, []*Utxo]{
+			subject: "3-Slab",
+			builder: func(p utxoParams) func() []*Utxo {
+				pkScript := makePkScript(p.scriptSize)
+				alloc := p.alloc.or(allocShared)
+				return func() []*Utxo { return buildUtxoSlab(p.numUtxos, pkScript, alloc) }
+			},
+			len:  func(s *[]*Utxo) int { return len(*s) },
+			read: func(s *[]*Utxo, i int) int64 { return readUtxo((*s)[i]) },
+		},
+	},
+}
+
+// utxoDatasets is the standard lock-step Utxo progression.
+func utxoDatasets() []dataset[utxoParams] {
+	return generateDatasets(benchConfig[utxoParams]{
+		dims:       utxoDims(scaleGrowth(8, exponentialGrowth()), linearGrowth(34)),
+		iterations: 8,
+	})
+}
+
 // BenchmarkUtxo_SliceBuild benchmarks building slices of Utxo values vs pointers
 func BenchmarkUtxo_SliceBuild(b *testing.B) {
-	datasets := generateUtxoDatasets(utxoBenchConfig{
-		utxoGrowth:   scaleGrowth(8, exponentialGrowth()),
-		scriptGrowth: linearGrowth(34),
-		iterations:   8,
-	})
-	for _, d := range datasets {
-		prefix := d.name()
-		// Pre-build a constant script to be shared among all Utxos.
-		// This isolates the benchmark to the cost of building the slice
-		// of structs, not the cost of building the scripts themselves.
-		pkScript := make([]byte, d.scriptSize)
-		for j := 0; j < d.scriptSize; j++ {
-			pkScript[j] = byte(j)
-		}
-
-		b.Run(prefix+"/0-Values", func(b *testing.B) {
-			b.ReportAllocs()
-			var s []Utxo
-			for b.Loop() {
-				s = buildUtxoValues(d.numUtxos, pkScript)
-			}
-			sinkInt = len(s)
-		})
-		b.Run(prefix+"/1-Pointers", func(b *testing.B) {
-			b.ReportAllocs()
-			var s []*Utxo
-			for b.Loop() {
-				s = buildUtxoPointers(d.numUtxos, pkScript)
-			}
-			sinkInt = len(s)
-		})
-	}
+	utxoSuite.sliceBuild(b, utxoDatasets())
 }
 
 // BenchmarkUtxo_SliceIterate benchmarks iterating over slices of Utxo values vs pointers
 func BenchmarkUtxo_SliceIterate(b *testing.B) {
-	datasets := generateUtxoDatasets(utxoBenchConfig{
-		utxoGrowth:   scaleGrowth(8, exponentialGrowth()),
-		scriptGrowth: linearGrowth(34),
-		iterations:   8,
+	utxoSuite.sliceIterate(b, utxoDatasets())
+}
+
+// BenchmarkUtxo_SeqIterate benchmarks iter.Seq and iter.Seq2 iterators over
+// slices of Utxo values vs pointers next to the plain range loops.
+func BenchmarkUtxo_SeqIterate(b *testing.B) {
+	utxoSuite.seqIterate(b, utxoDatasets())
+}
+
+// BenchmarkUtxo_SliceIterateParallel benchmarks concurrent readers sharing one
+// slice of Utxo values vs pointers at GOMAXPROCS 1, 2, 4 and 8.
+func BenchmarkUtxo_SliceIterateParallel(b *testing.B) {
+	utxoSuite.sliceIterateParallel(b, utxoDatasets(), parallelProcs)
+}
+
+// utxoSweep is the full grid of 8..1024 UTXOs against 34..136 byte scripts,
+// so every count is measured with every script size.
+func utxoSweep() []dataset[utxoParams] {
+	dims := utxoDims(scaleGrowth(8, exponentialGrowth()), linearGrowth(34))
+	dims[1].steps = 4
+	return generateSweep(sweepConfig[utxoParams]{
+		dims:       dims,
+		iterations: 8,
 	})
-	for _, d := range datasets {
-		pkScript := make([]byte, d.scriptSize)
-		for j := 0; j < d.scriptSize; j++ {
-			pkScript[j] = byte(j)
-		}
-		vals := buildUtxoValues(d.numUtxos, pkScript)
-		ptrs := buildUtxoPointers(d.numUtxos, pkScript)
-		prefix := d.name()
-		b.Run(prefix+"/0-Values", func(b *testing.B) {
-			b.ReportAllocs()
-			var acc int64
-			for b.Loop() {
-				for _, val := range vals {
-					acc += int64(val.Amount) + int64(len(val.PkScript)) + int64(val.Confirmations)
-				}
-			}
-			sinkI64 = acc
-		})
-		b.Run(prefix+"/1-Pointers", func(b *testing.B) {
-			b.ReportAllocs()
-			var acc int64
-			for b.Loop() {
-				for _, ptr := range ptrs {
-					acc += int64(ptr.Amount) + int64(len(ptr.PkScript)) + int64(ptr.Confirmations)
-				}
-			}
-			sinkI64 = acc
-		})
-	}
+}
+
+// BenchmarkUtxo_SliceBuildSweep benchmarks building slices of Utxo values vs
+// pointers over the count x script grid, one curve per script size.
+// Example: "0512-Utxos/068-Script/0-Values".
+func BenchmarkUtxo_SliceBuildSweep(b *testing.B) {
+	runSweep(b, utxoSweep(), "Utxos", func(b *testing.B, d dataset[utxoParams], prefix string) {
+		utxoSuite.runBuild(b, prefix, d.p)
+	})
+}
+
+// BenchmarkUtxo_SliceIterateSweep benchmarks iterating over slices of Utxo
+// values vs pointers over the count x script grid, one curve per script size.
+func BenchmarkUtxo_SliceIterateSweep(b *testing.B) {
+	runSweep(b, utxoSweep(), "Utxos", func(b *testing.B, d dataset[utxoParams], prefix string) {
+		utxoSuite.runIterate(b, prefix, d.p)
+	})
+}
+
+// utxoScriptSuite builds Utxos whose pkScripts are real standard scripts,
+// generated once per dataset from the dataset's script mix and shared by the
+// builders as utxoSuite shares its single pkScript.
+var utxoScriptSuite = pvSuite[utxoParams, Utxo]{
+	builders: func(p utxoParams) (func() []Utxo, func() []*Utxo) {
+		scripts := p.mix.scripts(p.numUtxos)
+		return func() []Utxo { return buildUtxoValuesScripts(scripts) },
+			func() []*Utxo { return buildUtxoPointersScripts(scripts) }
+	},
+	read:        readUtxo,
+	sumValues:   sumUtxoValues,
+	sumPointers: sumUtxoPointers,
+}
+
+// utxoScriptDatasets is the grid of 8..1024 UTXOs against every script mix.
+func utxoScriptDatasets() []dataset[utxoParams] {
+	return generateSweep(sweepConfig[utxoParams]{
+		dims:       utxoScriptDims(scaleGrowth(8, exponentialGrowth())),
+		iterations: 8,
+	})
+}
+
+// BenchmarkUtxo_ScriptTypeSliceBuild benchmarks building slices of Utxo
+// values vs pointers that hold real P2PKH, P2WPKH, P2SH, P2WSH and P2TR
+// scripts.
+func BenchmarkUtxo_ScriptTypeSliceBuild(b *testing.B) {
+	utxoScriptSuite.sliceBuild(b, utxoScriptDatasets())
+}
+
+// BenchmarkUtxo_ScriptTypeSliceIterate benchmarks iterating over slices of
+// Utxo values vs pointers that hold real standard scripts.
+func BenchmarkUtxo_ScriptTypeSliceIterate(b *testing.B) {
+	utxoScriptSuite.sliceIterate(b, utxoScriptDatasets())
+}
+
+// utxoAllocDatasets is the grid of 8..1024 UTXOs with 34-byte scripts under
+// every script allocation strategy.
+func utxoAllocDatasets() []dataset[utxoParams] {
+	dims := utxoAllocDims(scaleGrowth(8, exponentialGrowth()), constantGrowth(34))
+	dims[1].steps = 1
+	return generateSweep(sweepConfig[utxoParams]{
+		dims:       dims,
+		iterations: 8,
+	})
+}
+
+// BenchmarkUtxo_ScriptAllocSliceBuild benchmarks building slices of Utxo
+// values vs pointers with shared, per-element and arena-backed scripts.
+func BenchmarkUtxo_ScriptAllocSliceBuild(b *testing.B) {
+	utxoSuite.sliceBuild(b, utxoAllocDatasets())
+}
+
+// BenchmarkUtxo_ScriptAllocSliceIterate benchmarks iterating over slices of
+// Utxo values vs pointers with shared, per-element and arena-backed scripts.
+func BenchmarkUtxo_ScriptAllocSliceIterate(b *testing.B) {
+	utxoSuite.sliceIterate(b, utxoAllocDatasets())
 }
 
 // BenchmarkUtxo_SliceBuildAndIterate benchmarks building and iterating over slices of Utxo values vs pointers
 func BenchmarkUtxo_SliceBuildAndIterate(b *testing.B) {
-	d := utxoDataset{
-		numUtxos:   128,
-		scriptSize: 64,
-		maxUtxos:   128,
-		maxScript:  64,
-	}
-	pkScript := make([]byte, d.scriptSize)
-	for j := 0; j < d.scriptSize; j++ {
-		pkScript[j] = byte(j)
-	}
-
-	for i := range 10 {
-		prefix := d.name() + fmt.Sprintf("NReads%d", i)
-		b.Run(prefix+"/0-Values", func(b *testing.B) {
-			b.ReportAllocs()
-			var acc int64
-			for b.Loop() {
-				vals := buildUtxoValues(d.numUtxos, pkScript)
-				for range i {
-					for _, val := range vals {
-						acc += int64(val.Amount) + int64(len(val.PkScript)) + int64(val.Confirmations)
-					}
-				}
-			}
-			sinkI64 = acc
-		})
-		b.Run(prefix+"/1-Pointers", func(b *testing.B) {
-			b.ReportAllocs()
-			var acc int64
-			for b.Loop() {
-				ptrs := buildUtxoPointers(d.numUtxos, pkScript)
-				for range i {
-					for _, ptr := range ptrs {
-						acc += int64(ptr.Amount) + int64(len(ptr.PkScript)) + int64(ptr.Confirmations)
-					}
-				}
-			}
-			sinkI64 = acc
-		})
-	}
-}
\ No newline at end of file
+	d := singleDataset(utxoDims(constantGrowth(128), constantGrowth(64)))
+	utxoSuite.sliceBuildAndIterate(b, d, 10)
+}
@@ -0,0 +1,243 @@
+package pv
+
+import (
+	"testing"
+	"unsafe"
+
+	"github.com/btcsuite/btcd/wire"
+)
+
+// largeUtxo pads a Utxo just past 128 bytes, the largest map element the
+// Go 1.24 swiss-table map stores inline in its slots. A Utxo, at 120 bytes,
+// sits in the slot next to its key, so growing the map moves whole Utxos
+// and every insert is allocation-free until the table grows. A larger
+// element is allocated on its own and the slot holds a pointer to it, which
+// makes a map of largeUtxo behave like a map of *Utxo the runtime manages.
+type largeUtxo struct {
+	Utxo
+	_ [128 + 1 - unsafe.Sizeof(Utxo{})]byte
+}
+
+// utxoMapOp is the map operation a UtxoMap benchmark times.
+type utxoMapOp int
+
+const (
+	mapInsert utxoMapOp = iota
+	mapLookupHit
+	mapLookupMiss
+	mapDelete
+	mapIterate
+)
+
+// utxoMapScriptSize is the shared pkScript size of the UtxoMap fixtures, a
+// P2WPKH script.
+const utxoMapScriptSize = 34
+
+// utxoMapReads are the read loops of one map layout, written per layout so
+// the value reads are inlined. lookup sums the values of the keys that are
+// in m; iterate sums every value of m.
+type utxoMapReads[V any] struct {
+	lookup  func(m map[wire.OutPoint]V, keys []wire.OutPoint) int64
+	iterate func(m map[wire.OutPoint]V) int64
+}
+
+// runUtxoMap registers one subject of a UtxoMap benchmark. build inserts
+// makeUtxoValue(i) under makeOutPointValue(i) for i below n into an unsized
+// map, and reads fold the map values into the checksum.
+//   - mapInsert times build.
+//   - mapLookupHit looks up every key, in insertion order.
+//   - mapLookupMiss looks up n keys that are not in the map.
+//   - mapDelete deletes every key and puts its value back, so the map is
+//     the same size at the start of every op.
+//   - mapIterate ranges over the whole map.
+func runUtxoMap[V any](b *testing.B, name string, op utxoMapOp, n int,
+	build func() map[wire.OutPoint]V, reads utxoMapReads[V]) {
+
+	b.Run(name, func(b *testing.B) {
+		b.ReportAllocs()
+		if op == mapInsert {
+			var m map[wire.OutPoint]V
+			gc := startGCMeter()
+			for b.Loop() {
+				m = build()
+			}
+			gc.report(b)
+			m = nil
+			b.ReportMetric(retainedObjects(func() { m = build() }), "heap-objs")
+			sinkInt = len(m)
+			return
+		}
+
+		m := build()
+		keys := make([]wire.OutPoint, n)
+		for i := range keys {
+			if op == mapLookupMiss {
+				keys[i] = makeOutPointValue(n + i)
+			} else {
+				keys[i] = makeOutPointValue(i)
+			}
+		}
+		gc := startGCMeter()
+		for b.Loop() {
+			var acc int64
+			switch op {
+			case mapLookupHit, mapLookupMiss:
+				acc = reads.lookup(m, keys)
+			case mapDelete:
+				for _, k := range keys {
+					v := m[k]
+					delete(m, k)
+					m[k] = v
+				}
+			case mapIterate:
+				acc = reads.iterate(m)
+			}
+			sinkI64 = acc
+		}
+		gc.report(b)
+	})
+}
+
+// benchUtxoMap runs op over the OutPoint datasets with Utxos keyed by
+// OutPoint as map[wire.OutPoint]Utxo, map[wire.OutPoint]*Utxo,
+// map[wire.OutPoint]int32 indexing into a []Utxo, and
+// map[wire.OutPoint]largeUtxo.
+func benchUtxoMap(b *testing.B, op utxoMapOp) {
+	pkScript := makePkScript(utxoMapScriptSize)
+	for _, d := range outpointDatasets() {
+		n := d.p.numOutPoints
+		prefix := d.name()
+		runUtxoMap(b, prefix+"/0-Values", op, n, func() map[wire.OutPoint]Utxo {
+			m := make(map[wire.OutPoint]Utxo)
+			for i := 0; i < n; i++ {
+				m[makeOutPointValue(i)] = makeUtxoValue(i, pkScript)
+			}
+			return m
+		}, utxoMapReads[Utxo]{
+			lookup: func(m map[wire.OutPoint]Utxo, keys []wire.OutPoint) int64 {
+				var acc int64
+				for _, k := range keys {
+					if u, ok := m[k]; ok {
+						acc += readUtxo(&u)
+					}
+				}
+				return acc
+			},
+			iterate: func(m map[wire.OutPoint]Utxo) int64 {
+				var acc int64
+				for _, u := range m {
+					acc += readUtxo(&u)
+				}
+				return acc
+			},
+		})
+
+		runUtxoMap(b, prefix+"/1-Pointers", op, n, func() map[wire.OutPoint]*Utxo {
+			m := make(map[wire.OutPoint]*Utxo)
+			for i := 0; i < n; i++ {
+				m[makeOutPointValue(i)] = makeUtxoPointer(i, pkScript)
+			}
+			return m
+		}, utxoMapReads[*Utxo]{
+			lookup: func(m map[wire.OutPoint]*Utxo, keys []wire.OutPoint) int64 {
+				var acc int64
+				for _, k := range keys {
+					if u, ok := m[k]; ok {
+						acc += readUtxo(u)
+					}
+				}
+				return acc
+			},
+			iterate: func(m map[wire.OutPoint]*Utxo) int64 {
+				var acc int64
+				for _, u := range m {
+					acc += readUtxo(u)
+				}
+				return acc
+			},
+		})
+
+		// The index subject's build replaces utxos, so reads always see
+		// the slice of the map being timed.
+		var utxos []Utxo
+		runUtxoMap(b, prefix+"/2-Index", op, n, func() map[wire.OutPoint]int32 {
+			utxos = buildUtxoValues(n, pkScript)
+			m := make(map[wire.OutPoint]int32)
+			for i := 0; i < n; i++ {
+				m[makeOutPointValue(i)] = int32(i)
+			}
+			return m
+		}, utxoMapReads[int32]{
+			lookup: func(m map[wire.OutPoint]int32, keys []wire.OutPoint) int64 {
+				var acc int64
+				for _, k := range keys {
+					if i, ok := m[k]; ok {
+						acc += readUtxo(&utxos[i])
+					}
+				}
+				return acc
+			},
+			iterate: func(m map[wire.OutPoint]int32) int64 {
+				var acc int64
+				for _, i := range m {
+					acc += readUtxo(&utxos[i])
+				}
+				return acc
+			},
+		})
+
+		runUtxoMap(b, prefix+"/3-LargeValues", op, n, func() map[wire.OutPoint]largeUtxo {
+			m := make(map[wire.OutPoint]largeUtxo)
+			for i := 0; i < n; i++ {
+				m[makeOutPointValue(i)] = largeUtxo{Utxo: makeUtxoValue(i, pkScript)}
+			}
+			return m
+		}, utxoMapReads[largeUtxo]{
+			lookup: func(m map[wire.OutPoint]largeUtxo, keys []wire.OutPoint) int64 {
+				var acc int64
+				for _, k := range keys {
+					if u, ok := m[k]; ok {
+						acc += readUtxo(&u.Utxo)
+					}
+				}
+				return acc
+			},
+			iterate: func(m map[wire.OutPoint]largeUtxo) int64 {
+				var acc int64
+				for _, u := range m {
+					acc += readUtxo(&u.Utxo)
+				}
+				return acc
+			},
+		})
+	}
+}
+
+// BenchmarkUtxoMap_Insert benchmarks building a Utxo set keyed by OutPoint
+// with each map layout.
+func BenchmarkUtxoMap_Insert(b *testing.B) {
+	benchUtxoMap(b, mapInsert)
+}
+
+// BenchmarkUtxoMap_LookupHit benchmarks looking up every Utxo of the set by
+// OutPoint.
+func BenchmarkUtxoMap_LookupHit(b *testing.B) {
+	benchUtxoMap(b, mapLookupHit)
+}
+
+// BenchmarkUtxoMap_LookupMiss benchmarks looking up OutPoints that are not in
+// the set.
+func BenchmarkUtxoMap_LookupMiss(b *testing.B) {
+	benchUtxoMap(b, mapLookupMiss)
+}
+
+// BenchmarkUtxoMap_Delete benchmarks deleting every Utxo of the set and
+// putting it back, as spends and new receives churn a wallet's set.
+func BenchmarkUtxoMap_Delete(b *testing.B) {
+	benchUtxoMap(b, mapDelete)
+}
+
+// BenchmarkUtxoMap_Iterate benchmarks ranging over the whole set.
+func BenchmarkUtxoMap_Iterate(b *testing.B) {
+	benchUtxoMap(b, mapIterate)
+}
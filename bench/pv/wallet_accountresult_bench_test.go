@@ -61,136 +61,159 @@ func buildAccountResultPointers(n int) []*wallet.AccountResult {
 	return s
 }
 
+// accountResultColumns is the struct-of-arrays layout of a
+// []wallet.AccountResult, limited to the fields the benchmarks read.
+type accountResultColumns struct {
+	AccountNumbers        []uint32
+	AccountNames          []string
+	ExternalKeyCounts     []uint32
+	InternalKeyCounts     []uint32
+	ImportedKeyCounts     []uint32
+	MasterKeyFingerprints []uint32
+	IsWatchOnly           []bool
+	TotalBalances         []btcutil.Amount
+}
+
+func buildAccountResultColumns(n int) accountResultColumns {
+	c := accountResultColumns{
+		AccountNumbers:        make([]uint32, n),
+		AccountNames:          make([]string, n),
+		ExternalKeyCounts:     make([]uint32, n),
+		InternalKeyCounts:     make([]uint32, n),
+		ImportedKeyCounts:     make([]uint32, n),
+		MasterKeyFingerprints: make([]uint32, n),
+		IsWatchOnly:           make([]bool, n),
+		TotalBalances:         make([]btcutil.Amount, n),
+	}
+	for i := 0; i < n; i++ {
+		c.AccountNumbers[i] = uint32(i)
+		c.AccountNames[i] = fmt.Sprintf("acct-%d", i)
+		c.ExternalKeyCounts[i] = uint32(i % 100)
+		c.InternalKeyCounts[i] = uint32((i + 7) % 100)
+		c.ImportedKeyCounts[i] = uint32((i + 13) % 100)
+		c.MasterKeyFingerprints[i] = uint32(i * 3)
+		c.IsWatchOnly[i] = i%2 == 0
+		c.TotalBalances[i] = btcutil.Amount(1000 + i)
+	}
+	return c
+}
+
+type accountParams struct {
+	numAccounts int
+}
+
+// accountDims names the AccountResult count dimension.
+// Example prefix: "0256-Accounts".
+func accountDims(label string, accountGrowth growthFunc) []dimension[accountParams] {
+	return []dimension[accountParams]{
+		{label: label, growth: accountGrowth, set: func(p *accountParams, v int) { p.numAccounts = v }},
+	}
+}
+
+// readAccountResult is the accessor summed by the AccountResult iterate benchmarks.
+func readAccountResult(a *wallet.AccountResult) int64 {
+	acc := int64(a.TotalBalance)
+	acc += int64(a.AccountNumber)
+	acc += int64(a.ExternalKeyCount)
+	acc += int64(a.InternalKeyCount)
+	acc += int64(a.ImportedKeyCount)
+	acc += int64(a.MasterKeyFingerprint)
+	acc += int64(len(a.AccountName))
+	if a.IsWatchOnly {
+		acc++
+	}
+	return acc
+}
+
+// sumAccountResultValues is readAccountResult over a []wallet.AccountResult.
+func sumAccountResultValues(vals []wallet.AccountResult) int64 {
+	var acc int64
+	for _, val := range vals {
+		acc += readAccountResult(&val)
+	}
+	return acc
+}
+
+// sumAccountResultPointers is readAccountResult over a []*wallet.AccountResult.
+func sumAccountResultPointers(ptrs []*wallet.AccountResult) int64 {
+	var acc int64
+	for _, ptr := range ptrs {
+		acc += readAccountResult(ptr)
+	}
+	return acc
+}
+
+// accountResultSuite keeps the "-OutPoints-Accounts" prefixes Build and
+// Iterate had when they reused the OutPoint datasets.
+var accountResultSuite = pvSuite[accountParams, wallet.AccountResult]{
+	builders: func(p accountParams) (func() []wallet.AccountResult, func() []*wallet.AccountResult) {
+		return func() []wallet.AccountResult { return buildAccountResultValues(p.numAccounts) },
+			func() []*wallet.AccountResult { return buildAccountResultPointers(p.numAccounts) }
+	},
+	read:        readAccountResult,
+	sumValues:   sumAccountResultValues,
+	sumPointers: sumAccountResultPointers,
+	layouts: []pvLayout[accountParams]{
+		layout[accountParams, accountResultColumns]{
+			subject: "2-SoA",
+			builder: func(p accountParams) func() accountResultColumns {
+				return func() accountResultColumns { return buildAccountResultColumns(p.numAccounts) }
+			},
+			len: func(c *accountResultColumns) int { return len(c.TotalBalances) },
+			read: func(c *accountResultColumns, i int) int64 {
+				acc := int64(c.TotalBalances[i])
+				acc += int64(c.AccountNumbers[i])
+				acc += int64(c.ExternalKeyCounts[i])
+				acc += int64(c.InternalKeyCounts[i])
+				acc += int64(c.ImportedKeyCounts[i])
+				acc += int64(c.MasterKeyFingerprints[i])
+				acc += int64(len(c.AccountNames[i]))
+				if c.IsWatchOnly[i] {
+					acc++
+				}
+				return acc
+			},
+		},
+	},
+	prefix: func(d dataset[accountParams]) string {
+		return fmt.Sprintf("%s-Accounts", d.name())
+	},
+}
+
+// accountResultDatasets is the standard AccountResult count progression.
+func accountResultDatasets() []dataset[accountParams] {
+	return generateDatasets(benchConfig[accountParams]{
+		dims:       accountDims("OutPoints", scaleGrowth(8, exponentialGrowth())),
+		iterations: 8,
+	})
+}
+
 // BenchmarkAccountResult_SliceBuild benchmarks building slices of AccountResult values vs pointers
 func BenchmarkAccountResult_SliceBuild(b *testing.B) {
-	// Reuse outpoint-style single-dimension datasets (count growth only).
-	datasets := generateOutPointDatasets(outpointBenchConfig{
-		outpointGrowth: scaleGrowth(8, exponentialGrowth()),
-		iterations:     8,
-	})
-	for _, d := range datasets {
-		prefix := fmt.Sprintf("%s-Accounts", d.name())
-		b.Run(prefix+"/0-Values", func(b *testing.B) {
-			b.ReportAllocs()
-			var s []wallet.AccountResult
-			for b.Loop() {
-				s = buildAccountResultValues(d.numOutPoints)
-			}
-			sinkInt = len(s)
-		})
-		b.Run(prefix+"/1-Pointers", func(b *testing.B) {
-			b.ReportAllocs()
-			var s []*wallet.AccountResult
-			for b.Loop() {
-				s = buildAccountResultPointers(d.numOutPoints)
-			}
-			sinkInt = len(s)
-		})
-	}
+	accountResultSuite.sliceBuild(b, accountResultDatasets())
 }
 
 // BenchmarkAccountResult_SliceIterate benchmarks iterating over slices of AccountResult values vs pointers
 func BenchmarkAccountResult_SliceIterate(b *testing.B) {
-	datasets := generateOutPointDatasets(outpointBenchConfig{
-		outpointGrowth: scaleGrowth(8, exponentialGrowth()),
-		iterations:     8,
-	})
-	for _, d := range datasets {
-		vals := buildAccountResultValues(d.numOutPoints)
-		ptrs := buildAccountResultPointers(d.numOutPoints)
-		prefix := fmt.Sprintf("%s-Accounts", d.name())
-		b.Run(prefix+"/0-Values", func(b *testing.B) {
-			b.ReportAllocs()
-			var acc int64
-			for b.Loop() {
-				for _, val := range vals {
-					acc += int64(val.TotalBalance)
-					acc += int64(val.AccountNumber)
-					acc += int64(val.ExternalKeyCount)
-					acc += int64(val.InternalKeyCount)
-					acc += int64(val.ImportedKeyCount)
-					acc += int64(val.MasterKeyFingerprint)
-					acc += int64(len(val.AccountName))
-					if val.IsWatchOnly {
-						acc++
-					}
-				}
-			}
-			sinkI64 = acc
-		})
-		b.Run(prefix+"/1-Pointers", func(b *testing.B) {
-			b.ReportAllocs()
-			var acc int64
-			for b.Loop() {
-				for _, ptr := range ptrs {
-					acc += int64(ptr.TotalBalance)
-					acc += int64(ptr.AccountNumber)
-					acc += int64(ptr.ExternalKeyCount)
-					acc += int64(ptr.InternalKeyCount)
-					acc += int64(ptr.ImportedKeyCount)
-					acc += int64(ptr.MasterKeyFingerprint)
-					acc += int64(len(ptr.AccountName))
-					if ptr.IsWatchOnly {
-						acc++
-					}
-				}
-			}
-			sinkI64 = acc
-		})
-	}
+	accountResultSuite.sliceIterate(b, accountResultDatasets())
+}
+
+// BenchmarkAccountResult_SeqIterate benchmarks iter.Seq and iter.Seq2 iterators over
+// slices of AccountResult values vs pointers next to the plain range loops.
+func BenchmarkAccountResult_SeqIterate(b *testing.B) {
+	accountResultSuite.seqIterate(b, accountResultDatasets())
+}
+
+// BenchmarkAccountResult_SliceIterateParallel benchmarks concurrent readers
+// sharing one slice of AccountResult values vs pointers at GOMAXPROCS 1, 2, 4
+// and 8.
+func BenchmarkAccountResult_SliceIterateParallel(b *testing.B) {
+	accountResultSuite.sliceIterateParallel(b, accountResultDatasets(), parallelProcs)
 }
 
 // BenchmarkAccountResult_SliceBuildAndIterate benchmarks building and iterating over slices
 // of AccountResult values vs pointers with repeated reads.
 func BenchmarkAccountResult_SliceBuildAndIterate(b *testing.B) {
-	// Fixed small dataset for build+iterate stress with repeated reads.
-	type ds struct{ n, max int }
-	d := ds{n: 256, max: 256}
-	for i := range 10 {
-		prefix := fmt.Sprintf("%0*d-AccountsNReads%d", len(fmt.Sprintf("%d", d.max)), d.n, i)
-		b.Run(prefix+"/0-Values", func(b *testing.B) {
-			b.ReportAllocs()
-			var acc int64
-			for b.Loop() {
-				vals := buildAccountResultValues(d.n)
-				for range i {
-					for _, val := range vals {
-						acc += int64(val.TotalBalance)
-						acc += int64(val.AccountNumber)
-						acc += int64(val.ExternalKeyCount)
-						acc += int64(val.InternalKeyCount)
-						acc += int64(val.ImportedKeyCount)
-						acc += int64(val.MasterKeyFingerprint)
-						acc += int64(len(val.AccountName))
-						if val.IsWatchOnly {
-							acc++
-						}
-					}
-				}
-			}
-			sinkI64 = acc
-		})
-		b.Run(prefix+"/1-Pointers", func(b *testing.B) {
-			b.ReportAllocs()
-			var acc int64
-			for b.Loop() {
-				ptrs := buildAccountResultPointers(d.n)
-				for range i {
-					for _, ptr := range ptrs {
-						acc += int64(ptr.TotalBalance)
-						acc += int64(ptr.AccountNumber)
-						acc += int64(ptr.ExternalKeyCount)
-						acc += int64(ptr.InternalKeyCount)
-						acc += int64(ptr.ImportedKeyCount)
-						acc += int64(ptr.MasterKeyFingerprint)
-						acc += int64(len(ptr.AccountName))
-						if ptr.IsWatchOnly {
-							acc++
-						}
-					}
-				}
-			}
-			sinkI64 = acc
-		})
-	}
+	d := singleDataset(accountDims("Accounts", constantGrowth(256)))
+	accountResultSuite.sliceBuildAndIterate(b, d, 10)
 }
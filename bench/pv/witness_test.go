@@ -0,0 +1,118 @@
+package pv
+
+import (
+	"fmt"
+
+	"github.com/btcsuite/btcd/wire"
+)
+
+// witnessShape is the witness stack of one input: the size of every item,
+// bottom of the stack first. The zero shape means no witness.
+type witnessShape struct {
+	name  string
+	items []int
+}
+
+// Witness presets for the common spend types. Signatures are DER-encoded
+// ECDSA with a sighash byte, 72 bytes at most, or 64-byte Schnorr with the
+// default sighash.
+var (
+	witnessNone = witnessShape{name: "None"}
+
+	// witnessP2WPKH is a signature and a compressed public key.
+	witnessP2WPKH = witnessShape{name: "P2WPKH", items: []int{72, 33}}
+
+	// witnessP2WSHMultisig spends a 2-of-3 multisig: the empty dummy item
+	// CHECKMULTISIG pops, two signatures and the 105-byte witness script.
+	witnessP2WSHMultisig = witnessShape{name: "P2WSH2of3", items: []int{0, 72, 72, 105}}
+
+	// witnessP2TRKeyPath is a single Schnorr signature.
+	witnessP2TRKeyPath = witnessShape{name: "P2TRKeyPath", items: []int{64}}
+)
+
+// witnessShapes are the shapes a witness dimension steps through.
+var witnessShapes = []witnessShape{
+	witnessNone,
+	witnessP2WPKH,
+	witnessP2WSHMultisig,
+	witnessP2TRKeyPath,
+}
+
+// uniformWitness returns a shape of count items of size bytes each, named
+// "<count>x<size>". Example: uniformWitness(2, 72) is "2x72".
+func uniformWitness(count, size int) witnessShape {
+	w := witnessShape{name: fmt.Sprintf("%dx%d", count, size), items: make([]int, count)}
+	for j := range w.items {
+		w.items[j] = size
+	}
+	return w
+}
+
+// makeWitness allocates a witness of the given shape, one buffer per item.
+func makeWitness(w witnessShape) wire.TxWitness {
+	if len(w.items) == 0 {
+		return nil
+	}
+	wit := make(wire.TxWitness, len(w.items))
+	for j, size := range w.items {
+		wit[j] = make([]byte, size)
+	}
+	return wit
+}
+
+// readWitness folds a witness into a benchmark checksum, reading every item's
+// length and its first byte, so each item's buffer is loaded.
+func readWitness(wit wire.TxWitness) int64 {
+	acc := int64(len(wit))
+	for _, item := range wit {
+		acc += int64(len(item))
+		if len(item) > 0 {
+			acc += int64(item[0])
+		}
+	}
+	return acc
+}
+
+// witnessDim is the categorical witness dimension. Its growth index selects
+// an entry of witnessShapes. Example segment: "-P2WPKH-Witness".
+func witnessDim[P any](set func(p *P, w witnessShape)) dimension[P] {
+	return dimension[P]{
+		label:  "Witness",
+		growth: func(i int) int { return i },
+		set:    func(p *P, v int) { set(p, witnessShapes[v]) },
+		steps:  len(witnessShapes),
+		format: func(v, _ int) string { return "-" + witnessShapes[v].name + "-Witness" },
+	}
+}
+
+// witnessGrowthDims are the witness item count and item size dimensions, in
+// that order: the count dimension sets the stack height and the size
+// dimension fills it with uniformWitness, so the grid covers stacks past the
+// presets. shape returns the witness field of p.
+// Example segment: "-4-WitnessItems-128-ItemBytes".
+func witnessGrowthDims[P any](shape func(p *P) *witnessShape, countGrowth, sizeGrowth growthFunc) []dimension[P] {
+	return []dimension[P]{
+		{
+			label:  "WitnessItems",
+			growth: countGrowth,
+			set:    func(p *P, v int) { *shape(p) = uniformWitness(v, 0) },
+		},
+		{
+			label:  "ItemBytes",
+			growth: sizeGrowth,
+			set: func(p *P, v int) {
+				w := shape(p)
+				*w = uniformWitness(len(w.items), v)
+			},
+		},
+	}
+}
+
+// sigScriptSize returns the signature script size of an input with the given
+// witness: native segwit spends leave the signature script empty.
+func sigScriptSize(scriptSize int, witness witnessShape) int {
+	if len(witness.items) > 0 {
+		return 0
+	}
+	return scriptSize
+}
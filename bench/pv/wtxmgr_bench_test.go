@@ -0,0 +1,315 @@
+package pv
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/btcsuite/btcwallet/wtxmgr"
+)
+
+// wtxmgrScriptSize is the pkScript length of every generated credit output.
+const wtxmgrScriptSize = 34
+
+// wtxmgrEpoch anchors the block and receive times of generated records.
+var wtxmgrEpoch = time.Unix(1700000000, 0)
+
+// makeBlockMeta returns the metadata of the block holding record i, with ten
+// records per block and ten minutes between blocks.
+func makeBlockMeta(i int) wtxmgr.BlockMeta {
+	height := 800000 + i/10
+	return wtxmgr.BlockMeta{
+		Block: wtxmgr.Block{
+			Hash:   chainhash.Hash{byte(height % 251), byte(height >> 8), byte(height >> 16)},
+			Height: int32(height),
+		},
+		Time: wtxmgrEpoch.Add(time.Duration(i/10) * 10 * time.Minute),
+	}
+}
+
+func makeCreditValue(i int, pkScript []byte) wtxmgr.Credit {
+	return wtxmgr.Credit{
+		OutPoint:     wire.OutPoint{Hash: chainhash.Hash{byte(i % 251)}, Index: uint32(i % 4)},
+		BlockMeta:    makeBlockMeta(i),
+		Amount:       btcutil.Amount(1000 + i),
+		PkScript:     pkScript,
+		Received:     wtxmgrEpoch.Add(time.Duration(i) * time.Minute),
+		FromCoinBase: i%100 == 0,
+	}
+}
+
+func makeCreditPointer(i int, pkScript []byte) *wtxmgr.Credit {
+	return &wtxmgr.Credit{
+		OutPoint:     wire.OutPoint{Hash: chainhash.Hash{byte(i % 251)}, Index: uint32(i % 4)},
+		BlockMeta:    makeBlockMeta(i),
+		Amount:       btcutil.Amount(1000 + i),
+		PkScript:     pkScript,
+		Received:     wtxmgrEpoch.Add(time.Duration(i) * time.Minute),
+		FromCoinBase: i%100 == 0,
+	}
+}
+
+// buildCreditValues constructs a slice of Credit values whose pkScripts are
+// allocated by alloc. Every script is len(pkScript) bytes.
+func buildCreditValues(n int, pkScript []byte, alloc scriptAlloc) []wtxmgr.Credit {
+	scripts := alloc.scripts(pkScript, n*len(pkScript))
+	s := make([]wtxmgr.Credit, n)
+	for i := 0; i < n; i++ {
+		s[i] = makeCreditValue(i, scripts.next(len(pkScript)))
+	}
+	return s
+}
+
+// buildCreditPointers is buildCreditValues for a slice of Credit pointers.
+func buildCreditPointers(n int, pkScript []byte, alloc scriptAlloc) []*wtxmgr.Credit {
+	scripts := alloc.scripts(pkScript, n*len(pkScript))
+	s := make([]*wtxmgr.Credit, n)
+	for i := 0; i < n; i++ {
+		s[i] = makeCreditPointer(i, scripts.next(len(pkScript)))
+	}
+	return s
+}
+
+// makeTxDetailsValue returns the details of a 2-in/2-out transaction that
+// debits its first input and credits its first output back to the wallet as
+// change. The hash is derived from i rather than computed, as a wallet reads
+// it from the store.
+func makeTxDetailsValue(i int) wtxmgr.TxDetails {
+	var label string
+	if i%4 == 0 {
+		label = fmt.Sprintf("payment-%d", i)
+	}
+	scripts := allocPerElement.scripts(nil, 0)
+	return wtxmgr.TxDetails{
+		TxRecord: wtxmgr.TxRecord{
+			MsgTx:    makeMsgTxValue(i, 2, 2, wtxmgrScriptSize, witnessNone, &scripts),
+			Hash:     chainhash.Hash{byte(i % 251), byte(i >> 8), byte(i >> 16)},
+			Received: wtxmgrEpoch.Add(time.Duration(i) * time.Minute),
+		},
+		Block: makeBlockMeta(i),
+		Credits: []wtxmgr.CreditRecord{
+			{Amount: btcutil.Amount(1000 + i), Index: 0, Change: true},
+		},
+		Debits: []wtxmgr.DebitRecord{
+			{Amount: btcutil.Amount(5000 + i), Index: 0},
+		},
+		Label: label,
+	}
+}
+
+func makeTxDetailsPointer(i int) *wtxmgr.TxDetails {
+	d := makeTxDetailsValue(i)
+	return &d
+}
+
+func buildTxDetailsValues(n int) []wtxmgr.TxDetails {
+	s := make([]wtxmgr.TxDetails, n)
+	for i := 0; i < n; i++ {
+		s[i] = makeTxDetailsValue(i)
+	}
+	return s
+}
+
+func buildTxDetailsPointers(n int) []*wtxmgr.TxDetails {
+	s := make([]*wtxmgr.TxDetails, n)
+	for i := 0; i < n; i++ {
+		s[i] = makeTxDetailsPointer(i)
+	}
+	return s
+}
+
+type creditParams struct {
+	numCredits int
+
+	// alloc is how pkScripts are allocated. The default makes one per
+	// element, as records decoded from the store each own their script.
+	alloc scriptAlloc
+}
+
+// creditDims names the Credit count dimension.
+// Example prefix: "04096-Credits".
+func creditDims(creditGrowth growthFunc) []dimension[creditParams] {
+	return []dimension[creditParams]{
+		{label: "Credits", growth: creditGrowth, set: func(p *creditParams, v int) { p.numCredits = v }},
+	}
+}
+
+// creditAllocDims is creditDims with a script allocation dimension.
+// Example prefix: "04096-Credits-Arena".
+func creditAllocDims(creditGrowth growthFunc) []dimension[creditParams] {
+	return append(creditDims(creditGrowth),
+		allocDim(func(p *creditParams, a scriptAlloc) { p.alloc = a }))
+}
+
+type txDetailsParams struct {
+	numTxDetails int
+}
+
+// txDetailsDims names the TxDetails count dimension.
+// Example prefix: "04096-TxDetails".
+func txDetailsDims(txDetailsGrowth growthFunc) []dimension[txDetailsParams] {
+	return []dimension[txDetailsParams]{
+		{label: "TxDetails", growth: txDetailsGrowth, set: func(p *txDetailsParams, v int) { p.numTxDetails = v }},
+	}
+}
+
+// readCredit is the accessor summed by the Credit iterate benchmarks.
+func readCredit(c *wtxmgr.Credit) int64 {
+	acc := int64(c.Amount) + int64(len(c.PkScript))
+	acc += int64(c.Height) + int64(c.Index)
+	if c.FromCoinBase {
+		acc++
+	}
+	return acc
+}
+
+// sumCreditValues is readCredit over a []wtxmgr.Credit.
+func sumCreditValues(vals []wtxmgr.Credit) int64 {
+	var acc int64
+	for _, val := range vals {
+		acc += readCredit(&val)
+	}
+	return acc
+}
+
+// sumCreditPointers is readCredit over a []*wtxmgr.Credit.
+func sumCreditPointers(ptrs []*wtxmgr.Credit) int64 {
+	var acc int64
+	for _, ptr := range ptrs {
+		acc += readCredit(ptr)
+	}
+	return acc
+}
+
+var creditSuite = pvSuite[creditParams, wtxmgr.Credit]{
+	builders: func(p creditParams) (func() []wtxmgr.Credit, func() []*wtxmgr.Credit) {
+		pkScript := makePkScript(wtxmgrScriptSize)
+		alloc := p.alloc.or(allocPerElement)
+		return func() []wtxmgr.Credit { return buildCreditValues(p.numCredits, pkScript, alloc) },
+			func() []*wtxmgr.Credit { return buildCreditPointers(p.numCredits, pkScript, alloc) }
+	},
+	read:        readCredit,
+	sumValues:   sumCreditValues,
+	sumPointers: sumCreditPointers,
+}
+
+// readTxDetails is the accessor summed by the TxDetails iterate benchmarks.
+func readTxDetails(d *wtxmgr.TxDetails) int64 {
+	acc := int64(d.Block.Height) + int64(len(d.Label))
+	for _, c := range d.Credits {
+		acc += int64(c.Amount)
+	}
+	for _, db := range d.Debits {
+		acc -= int64(db.Amount)
+	}
+	for _, to := range d.MsgTx.TxOut {
+		acc += to.Value
+	}
+	return acc
+}
+
+// sumTxDetailsValues is readTxDetails over a []wtxmgr.TxDetails.
+func sumTxDetailsValues(vals []wtxmgr.TxDetails) int64 {
+	var acc int64
+	for _, val := range vals {
+		acc += readTxDetails(&val)
+	}
+	return acc
+}
+
+// sumTxDetailsPointers is readTxDetails over a []*wtxmgr.TxDetails.
+func sumTxDetailsPointers(ptrs []*wtxmgr.TxDetails) int64 {
+	var acc int64
+	for _, ptr := range ptrs {
+		acc += readTxDetails(ptr)
+	}
+	return acc
+}
+
+// txDetailsSuite reads the balance delta of each transaction, the way a
+// wallet summarises its history, along with the outputs it pays.
+var txDetailsSuite = pvSuite[txDetailsParams, wtxmgr.TxDetails]{
+	builders: func(p txDetailsParams) (func() []wtxmgr.TxDetails, func() []*wtxmgr.TxDetails) {
+		return func() []wtxmgr.TxDetails { return buildTxDetailsValues(p.numTxDetails) },
+			func() []*wtxmgr.TxDetails { return buildTxDetailsPointers(p.numTxDetails) }
+	},
+	read:        readTxDetails,
+	sumValues:   sumTxDetailsValues,
+	sumPointers: sumTxDetailsPointers,
+}
+
+// creditDatasets is the standard Credit count progression.
+func creditDatasets() []dataset[creditParams] {
+	return generateDatasets(benchConfig[creditParams]{
+		dims:       creditDims(scaleGrowth(8, exponentialGrowth())),
+		iterations: 8,
+	})
+}
+
+// creditAllocDatasets is the grid of 8..1024 Credits under every script
+// allocation strategy.
+func creditAllocDatasets() []dataset[creditParams] {
+	return generateSweep(sweepConfig[creditParams]{
+		dims:       creditAllocDims(scaleGrowth(8, exponentialGrowth())),
+		iterations: 8,
+	})
+}
+
+// txDetailsDatasets is the standard TxDetails count progression.
+func txDetailsDatasets() []dataset[txDetailsParams] {
+	return generateDatasets(benchConfig[txDetailsParams]{
+		dims:       txDetailsDims(scaleGrowth(4, exponentialGrowth())),
+		iterations: 8,
+	})
+}
+
+// BenchmarkCredit_SliceBuild benchmarks building slices of wtxmgr.Credit values vs pointers
+func BenchmarkCredit_SliceBuild(b *testing.B) {
+	creditSuite.sliceBuild(b, creditDatasets())
+}
+
+// BenchmarkCredit_SliceIterate benchmarks iterating over slices of wtxmgr.Credit values vs pointers
+func BenchmarkCredit_SliceIterate(b *testing.B) {
+	creditSuite.sliceIterate(b, creditDatasets())
+}
+
+// BenchmarkCredit_ScriptAllocSliceBuild benchmarks building slices of
+// wtxmgr.Credit values vs pointers with shared, per-element and arena-backed
+// pkScripts.
+func BenchmarkCredit_ScriptAllocSliceBuild(b *testing.B) {
+	creditSuite.sliceBuild(b, creditAllocDatasets())
+}
+
+// BenchmarkCredit_ScriptAllocSliceIterate benchmarks iterating over slices of
+// wtxmgr.Credit values vs pointers with shared, per-element and arena-backed
+// pkScripts.
+func BenchmarkCredit_ScriptAllocSliceIterate(b *testing.B) {
+	creditSuite.sliceIterate(b, creditAllocDatasets())
+}
+
+// BenchmarkCredit_SliceBuildAndIterate benchmarks building and iterating over slices
+// of wtxmgr.Credit values vs pointers with repeated reads.
+func BenchmarkCredit_SliceBuildAndIterate(b *testing.B) {
+	d := singleDataset(creditDims(constantGrowth(128)))
+	creditSuite.sliceBuildAndIterate(b, d, 10)
+}
+
+// BenchmarkTxDetails_SliceBuild benchmarks building slices of wtxmgr.TxDetails values vs pointers
+func BenchmarkTxDetails_SliceBuild(b *testing.B) {
+	txDetailsSuite.sliceBuild(b, txDetailsDatasets())
+}
+
+// BenchmarkTxDetails_SliceIterate benchmarks iterating over slices of wtxmgr.TxDetails values vs pointers
+func BenchmarkTxDetails_SliceIterate(b *testing.B) {
+	txDetailsSuite.sliceIterate(b, txDetailsDatasets())
+}
+
+// BenchmarkTxDetails_SliceBuildAndIterate benchmarks building and iterating over slices
+// of wtxmgr.TxDetails values vs pointers with repeated reads.
+func BenchmarkTxDetails_SliceBuildAndIterate(b *testing.B) {
+	d := singleDataset(txDetailsDims(constantGrowth(256)))
+	txDetailsSuite.sliceBuildAndIterate(b, d, 10)
+}